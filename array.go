@@ -0,0 +1,131 @@
+/******************************************************
+# DESC    : java array types
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 22:00
+# FILE    : array.go
+******************************************************/
+
+// refers to com.caucho.hessian.io.ArraySerializer and BasicSerializer,
+// which write the java array as a typed list such as "[int" and "[com.foo.Bar",
+// except that byte[] is written as binary.
+
+package hessian
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// the java array component types of go basic kinds
+	javaArrayElems = map[reflect.Kind]string{
+		reflect.Bool:    "boolean",
+		reflect.Int8:    "byte",
+		reflect.Int16:   "short",
+		reflect.Int32:   "int",
+		reflect.Int:     "long", // int is encoded as long
+		reflect.Int64:   "long",
+		reflect.Float32: "float",
+		reflect.Float64: "double",
+		reflect.String:  "string",
+	}
+
+	// the go slice element types of java array component types
+	goArrayElems = map[string]reflect.Type{
+		"boolean": reflect.TypeOf(false),
+		"byte":    reflect.TypeOf(int8(0)),
+		"short":   reflect.TypeOf(int16(0)),
+		"int":     reflect.TypeOf(int32(0)),
+		"long":    reflect.TypeOf(int64(0)),
+		"float":   reflect.TypeOf(float32(0)),
+		"double":  reflect.TypeOf(float64(0)),
+		"string":  reflect.TypeOf(""),
+		"date":    reflect.TypeOf(time.Time{}),
+		"object":  reflect.TypeOf((*Any)(nil)).Elem(),
+	}
+)
+
+// getArrayName returns the java array type of go slice or array type @typ,
+// such as []int32 -> "[int", [][]string -> "[[string" and []Car -> "[example.Car".
+// []int8 is "[byte" while []byte is binary, and [][]byte is "[[byte" whose
+// items are binary. The return value ok is false if the element type of @typ
+// has no java type, such as map, interface{} and HessianMarshaler, which are
+// encoded as untyped list.
+func getArrayName(typ reflect.Type) (string, bool) {
+	if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+		return "", false
+	}
+
+	var elem = typ.Elem()
+	if s, ok := getSerializer(elem); ok {
+		return "[" + s.javaType, s.javaType != ""
+	}
+	switch {
+	case elem.Implements(marshalerType):
+		return "", false
+	case elem == timeType:
+		return "[date", true
+	case elem.Implements(pojoType):
+		return "[" + getPOJOType(elem), true
+	case elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.Uint8:
+		return "[[byte", true
+	case elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array:
+		if name, ok := getArrayName(elem); ok {
+			return "[" + name, true
+		}
+		return "", false
+	}
+	if e, ok := getJavaEnumOf(elem); ok {
+		return "[" + e.javaType, true
+	}
+	if name, ok := javaArrayElems[elem.Kind()]; ok {
+		return "[" + name, true
+	}
+
+	return "", false
+}
+
+// getArrayType returns the go slice type of java array type @javaType,
+// such as "[int" -> []int32 and "[com.foo.Bar" -> []*Bar if com.foo.Bar is a
+// POJO registered in @pojos. The return value ok is false if @javaType is not
+// an array type or its component type is unknown.
+func getArrayType(javaType string, pojos *POJORegistry) (reflect.Type, bool) {
+	if !strings.HasPrefix(javaType, "[") {
+		return nil, false
+	}
+
+	var (
+		ok   bool
+		name = javaType[1:]
+		elem reflect.Type
+	)
+	switch {
+	case name == "[byte":
+		elem = bytesType
+	case strings.HasPrefix(name, "["):
+		if elem, ok = getArrayType(name, pojos); !ok {
+			return nil, false
+		}
+	default:
+		if elem, ok = goArrayElems[name]; ok {
+			break
+		}
+		if inst := getPOJORegistry(pojos).createInstance(name); inst != nil {
+			elem = reflect.TypeOf(inst)
+			break
+		}
+		if e, ok := getJavaEnum(name); ok {
+			elem = e.goType
+			break
+		}
+		if s, ok := getJavaSerializer(name); ok {
+			elem = s.goType
+			break
+		}
+		return nil, false
+	}
+
+	return reflect.SliceOf(elem), true
+}
@@ -0,0 +1,79 @@
+/******************************************************
+# DESC    : array.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 22:00
+# FILE    : array_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// go test -v -run TestJavaArray
+
+func TestJavaArray(t *testing.T) {
+	var cases = []struct {
+		v   Any
+		typ string
+	}{
+		{[]int32{1, -1}, "[int"},
+		{[]int64{1 << 40}, "[long"},
+		{[]int16{-2}, "[short"},
+		{[]int8{-3, 4}, "[byte"},
+		{[]float64{1.5}, "[double"},
+		{[]float32{2.5}, "[float"},
+		{[]bool{true, false}, "[boolean"},
+		{[]string{"a", ""}, "[string"},
+		{[]time.Time{time.Unix(1760000000, 0)}, "[date"},
+		{[][]int32{{1}, {}}, "[[int"},
+		{[][]byte{{1, 2}, {3}}, "[[byte"},
+		{[]*Account{{Name: "alex"}}, "[test.Account"},
+		{[]Color{RED, BLUE}, "[com.foo.Color"},
+	}
+
+	for _, c := range cases {
+		if name, ok := getArrayName(reflect.TypeOf(c.v)); !ok || name != c.typ {
+			t.Errorf("getArrayName(%T) = %s, want %s", c.v, name, c.typ)
+		}
+
+		b := Encode(c.v, nil)
+		if want := append([]byte{'V'}, encType(c.typ, nil)...); !bytes.HasPrefix(b, want) {
+			t.Errorf("Encode(%#v) = %s", c.v, SprintHex(b))
+		}
+		if v, err := NewDecoder(b).Decode(); err != nil || !reflect.DeepEqual(v, c.v) {
+			t.Errorf("Decode(Encode(%#v)) = %#v, error:%v", c.v, v, err)
+		}
+
+		e := NewEncoder2()
+		if err := e.Encode(c.v); err != nil {
+			t.Fatalf("Encoder2.Encode(%#v) = error:%v", c.v, err)
+		}
+		if v, err := NewDecoder2(e.Buffer()).Decode(); err != nil || !reflect.DeepEqual(v, c.v) {
+			t.Errorf("Decoder2.Decode(Encode(%#v)) = %#v, error:%v", c.v, v, err)
+		}
+	}
+
+	// []byte is binary, and the slices of unknown java types are untyped lists
+	for _, v := range []Any{[]byte{1}, []map[string]int32{}, []Any{}, [][]Any{}} {
+		if name, ok := getArrayName(reflect.TypeOf(v)); ok {
+			t.Errorf("getArrayName(%T) = %s", v, name)
+		}
+	}
+
+	// "[object" and the array of unknown class are decoded as []Any
+	for _, typ := range []string{"[object", "[com.foo.Unknown"} {
+		e := NewEncoder2()
+		e.buffer = append(e.buffer, BC_LIST_DIRECT+1)
+		e.encType(typ)
+		e.encInt32(1)
+		if v, err := NewDecoder2(e.Buffer()).Decode(); err != nil || !reflect.DeepEqual(v, []Any{int32(1)}) {
+			t.Errorf("Decode(%s) = %#v, error:%v", typ, v, err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/******************************************************
+# DESC    : java.math.BigDecimal and java.math.BigInteger
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 19:40
+# FILE    : bignumber.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+const (
+	JAVA_BIG_DECIMAL = "java.math.BigDecimal"
+	JAVA_BIG_INTEGER = "java.math.BigInteger"
+)
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+)
+
+func init() {
+	registerBuiltinPOJO(BigDecimal{})
+	registerBuiltinPOJO(BigInteger{})
+}
+
+// BigDecimal is a java.math.BigDecimal, which is serialized by hessian as an
+// object whose only field "value" is the decimal string, such as "123.45".
+// *big.Float is encoded as a BigDecimal too.
+type BigDecimal struct {
+	Value string
+}
+
+func NewBigDecimal(f *big.Float) BigDecimal {
+	return BigDecimal{Value: f.Text('f', -1)}
+}
+
+func (BigDecimal) GetType() string {
+	return JAVA_BIG_DECIMAL
+}
+
+func (d BigDecimal) GetValue() string {
+	return d.Value
+}
+
+func (d *BigDecimal) SetValue(value string) {
+	d.Value = value
+}
+
+func (d BigDecimal) String() string {
+	return d.Value
+}
+
+// Float parses the decimal string, which may be in scientific notation
+// such as "1E+3".
+func (d BigDecimal) Float() (*big.Float, error) {
+	// a decimal digit needs less than 4 bits
+	var prec = uint(64)
+	if uint(len(d.Value))*4 > prec {
+		prec = uint(len(d.Value)) * 4
+	}
+
+	var f, ok = new(big.Float).SetPrec(prec).SetString(d.Value)
+	if !ok {
+		return nil, fmt.Errorf("illegal %s %q", JAVA_BIG_DECIMAL, d.Value)
+	}
+
+	return f, nil
+}
+
+// BigInteger is a java.math.BigInteger, which is serialized by hessian as an
+// object of its fields, and only "signum" and "mag" are necessary.
+// Mag is the magnitude in big-endian order, whose first int is non-zero.
+// *big.Int is encoded as a BigInteger too.
+type BigInteger struct {
+	Signum int32
+	Mag    []int32
+}
+
+func NewBigInteger(i *big.Int) BigInteger {
+	var (
+		b = i.Bytes()
+		n = (len(b) + 3) / 4
+		v = BigInteger{Signum: int32(i.Sign()), Mag: make([]int32, n)}
+	)
+
+	// pad the magnitude to a multiple of 4 bytes
+	b = append(make([]byte, n*4-len(b)), b...)
+	for j := 0; j < n; j++ {
+		v.Mag[j] = UnpackInt32(b[j*4 : j*4+4])
+	}
+
+	return v
+}
+
+func (BigInteger) GetType() string {
+	return JAVA_BIG_INTEGER
+}
+
+func (i BigInteger) GetSignum() int32 {
+	return i.Signum
+}
+
+func (i BigInteger) GetMag() []int32 {
+	return i.Mag
+}
+
+func (i *BigInteger) SetSignum(signum int32) {
+	i.Signum = signum
+}
+
+func (i *BigInteger) SetMag(mag []int32) {
+	i.Mag = mag
+}
+
+func (i BigInteger) Int() *big.Int {
+	var (
+		b = make([]byte, 0, len(i.Mag)*4)
+		v = new(big.Int)
+	)
+
+	for _, m := range i.Mag {
+		b = append(b, PackInt32(m)...)
+	}
+	v.SetBytes(b)
+	if i.Signum < 0 {
+		v.Neg(v)
+	}
+
+	return v
+}
+
+func (i BigInteger) String() string {
+	return i.Int().String()
+}
+
+// convertBigNumber converts the decoded BigDecimal or BigInteger @v to
+// *big.Float, *big.Int or string of type @typ. The return value ok is false
+// if @v is not a big number or @typ is not supported.
+func convertBigNumber(v Any, typ reflect.Type) (rv reflect.Value, ok bool, err error) {
+	var f *big.Float
+
+	switch n := v.(type) {
+	case *BigDecimal:
+		return convertBigNumber(*n, typ)
+	case *BigInteger:
+		return convertBigNumber(*n, typ)
+
+	case BigInteger:
+		switch typ {
+		case bigIntType:
+			return reflect.ValueOf(n.Int()), true, nil
+		case bigFloatType:
+			return reflect.ValueOf(new(big.Float).SetInt(n.Int())), true, nil
+		}
+
+	case BigDecimal:
+		if typ.Kind() == reflect.String {
+			return reflect.ValueOf(n.Value).Convert(typ), true, nil
+		}
+		if typ != bigIntType && typ != bigFloatType {
+			break
+		}
+		if f, err = n.Float(); err != nil {
+			return rv, true, err
+		}
+		if typ == bigFloatType {
+			return reflect.ValueOf(f), true, nil
+		}
+		if !f.IsInt() {
+			return rv, true, fmt.Errorf("%s %s is not an integer", JAVA_BIG_DECIMAL, n.Value)
+		}
+		i, _ := f.Int(nil)
+		return reflect.ValueOf(i), true, nil
+	}
+
+	return rv, false, nil
+}
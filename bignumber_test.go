@@ -0,0 +1,161 @@
+/******************************************************
+# DESC    : bignumber.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 19:40
+# FILE    : bignumber_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// go test -v -run TestBig
+
+type Account struct {
+	Name    string
+	Balance *big.Float
+}
+
+func (Account) GetType() string {
+	return "test.Account"
+}
+
+func (a Account) GetName() string {
+	return a.Name
+}
+
+func (a Account) GetBalance() *big.Float {
+	return a.Balance
+}
+
+func (a *Account) SetName(name string) {
+	a.Name = name
+}
+
+func (a *Account) SetBalance(balance *big.Float) {
+	a.Balance = balance
+}
+
+func init() {
+	RegisterPOJO(Account{})
+}
+
+func TestBigInteger(t *testing.T) {
+	var cases = []struct {
+		s      string
+		signum int32
+		mag    []int32
+	}{
+		{"0", 0, []int32{}},
+		{"1", 1, []int32{1}},
+		{"-1", -1, []int32{1}},
+		{"4294967296", 1, []int32{1, 0}},
+		{"-4294967295", -1, []int32{-1}},
+		{"1267650600228229401496703205381", 1, []int32{16, 0, 0, 5}},
+	}
+
+	for _, c := range cases {
+		i, _ := new(big.Int).SetString(c.s, 10)
+		v := NewBigInteger(i)
+		if v.Signum != c.signum || !reflect.DeepEqual(v.Mag, c.mag) || v.String() != c.s {
+			t.Errorf("NewBigInteger(%s) = %#v", c.s, v)
+		}
+
+		e := NewEncoder2()
+		if err := e.Encode(i); err != nil {
+			t.Fatalf("Encode(%s) = error:%v", c.s, err)
+		}
+		res, err := NewDecoder2(e.Buffer()).Decode()
+		if err != nil {
+			t.Fatalf("Decode(%s) = error:%v", c.s, err)
+		}
+		var got *big.Int
+		if err = ConvertTo(res, &got); err != nil || got.Cmp(i) != 0 {
+			t.Errorf("ConvertTo(%#v) = %v, error:%v", res, got, err)
+		}
+		if res, err = NewDecoder(Encode(i, nil)).Decode(); err != nil {
+			t.Fatalf("Decode(%s) = error:%v", c.s, err)
+		}
+		if err = ConvertTo(res, &got); err != nil || got.Cmp(i) != 0 {
+			t.Errorf("ConvertTo(%#v) = %v, error:%v", res, got, err)
+		}
+	}
+
+	// the java provider writes all the fields of java.math.BigInteger
+	e := NewEncoder2()
+	e.buffer = append(e.buffer, BC_OBJECT_DEF)
+	e.encString(JAVA_BIG_INTEGER)
+	e.encInt32(6)
+	for _, field := range []string{"signum", "mag", "bitCount", "bitLength", "lowestSetBit", "firstNonzeroIntNum"} {
+		e.encString(field)
+	}
+	e.buffer = append(e.buffer, BC_OBJECT_DIRECT)
+	e.encInt32(-1)
+	e.Encode([]Any{int32(16), int32(0)})
+	for i := 0; i < 4; i++ {
+		e.encInt32(0)
+	}
+	res, err := NewDecoder2(e.Buffer()).Decode()
+	if err != nil || res.(*BigInteger).String() != "-68719476736" {
+		t.Errorf("Decode(BigInteger) = %#v, error:%v", res, err)
+	}
+}
+
+func TestBigDecimal(t *testing.T) {
+	// the java provider writes java.math.BigDecimal as a string value object
+	e := NewEncoder2()
+	e.buffer = append(e.buffer, BC_OBJECT_DEF)
+	e.encString(JAVA_BIG_DECIMAL)
+	e.encInt32(1)
+	e.encString("value")
+	for _, s := range []string{"123.45", "-1E+3", "12345678901234567890.123456789"} {
+		e.buffer = append(e.buffer, BC_OBJECT_DIRECT)
+		e.encString(s)
+	}
+	d := NewDecoder2(e.Buffer())
+	for _, s := range []string{"123.45", "-1000", "12345678901234567890.123456789"} {
+		res, err := d.Decode()
+		if err != nil {
+			t.Fatalf("Decode(BigDecimal) = error:%v", err)
+		}
+		var f *big.Float
+		if err = ConvertTo(res, &f); err != nil || f.Text('f', -1) != s {
+			t.Errorf("ConvertTo(%#v) = %v, error:%v", res, f, err)
+		}
+	}
+
+	// *big.Float is encoded as BigDecimal
+	var (
+		f, _ = new(big.Float).SetString("0.1")
+		a    = Account{Name: "alex", Balance: f}
+	)
+	res, err := NewDecoder(Encode(a, nil)).Decode()
+	if err != nil || res.(*Account).Balance.Cmp(f) != 0 {
+		t.Errorf("Decode(Encode(%#v)) = %#v, error:%v", a, res, err)
+	}
+	e.Reset()
+	e.Encode(f)
+	res, err = NewDecoder2(e.Buffer()).Decode()
+	if err != nil || !reflect.DeepEqual(res, &BigDecimal{Value: "0.1"}) {
+		t.Errorf("Decode(Encode(%v)) = %#v, error:%v", f, res, err)
+	}
+
+	var (
+		s string
+		i *big.Int
+	)
+	if err = ConvertTo(res, &s); err != nil || s != "0.1" {
+		t.Errorf("ConvertTo(string) = %q, error:%v", s, err)
+	}
+	if err = ConvertTo(res, &i); err == nil {
+		t.Errorf("ConvertTo(*big.Int) = %v", i)
+	}
+	if err = ConvertTo(BigDecimal{Value: "1E+3"}, &i); err != nil || i.Int64() != 1000 {
+		t.Errorf("ConvertTo(*big.Int) = %v, error:%v", i, err)
+	}
+}
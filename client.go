@@ -14,32 +14,115 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 )
 
 type hessianRequest struct {
 	body []byte
 }
 
+// the way to name an overloaded java method, refers to
+// com.caucho.hessian.client.HessianProxyFactory:setOverloadEnabled
+type OverloadMode int
+
+const (
+	OVERLOAD_NONE  OverloadMode = iota // add
+	OVERLOAD_ARGC                      // add__2
+	OVERLOAD_TYPES                     // add_int_int
+)
+
+// Client calls the methods of the hessian service on @url.
+type Client struct {
+	url      string
+	overload OverloadMode
+	pojos    *POJORegistry
+	unknown  UnknownTypePolicy
+	handler  UnknownTypeHandler
+}
+
+func NewClient(url string) *Client {
+	return &Client{url: url}
+}
+
+func (this *Client) URL() string {
+	return this.url
+}
+
+// SetOverload sets the way to mangle the method name so that the overloaded
+// java methods can be called on the caucho server with overload support.
+// OVERLOAD_ARGC mangles the name by the count of the arguments(add__2),
+// OVERLOAD_TYPES mangles the name by the hessian types of the arguments(add_int_int).
+func (this *Client) SetOverload(mode OverloadMode) {
+	this.overload = mode
+}
+
+// SetPOJORegistry makes the client decode the replies by registry @r instead
+// of the global registry of RegisterPOJO, see Decoder.SetPOJORegistry.
+func (this *Client) SetPOJORegistry(r *POJORegistry) {
+	this.pojos = r
+}
+
+// SetUnknownTypePolicy sets the way to decode the object of unknown java
+// class in the replies, see Decoder.SetUnknownTypePolicy.
+func (this *Client) SetUnknownTypePolicy(policy UnknownTypePolicy) {
+	this.unknown = policy
+}
+
+// SetUnknownTypeHandler makes the client decode the object of unknown java
+// class in the replies by @handler, see Decoder.SetUnknownTypeHandler.
+func (this *Client) SetUnknownTypeHandler(handler UnknownTypeHandler) {
+	this.handler = handler
+}
+
+// mangle returns the method name sent to the server.
+func (this *Client) mangle(method string, args []Any) string {
+	switch this.overload {
+	case OVERLOAD_ARGC:
+		return mangleName(method, len(args))
+	case OVERLOAD_TYPES:
+		var name = method
+		for _, arg := range args {
+			name += "_" + mangleValueType(arg)
+		}
+		return name
+	}
+
+	return method
+}
+
+// mangleValueType returns the mangled type name of @v by mangleType, which
+// follows caucho, so the overloaded methods of both the caucho server and the
+// go server can be called.
+func mangleValueType(v Any) string {
+	if v == nil {
+		return "Object"
+	}
+
+	return mangleType(reflect.TypeOf(v))
+}
+
 //向hessian服务发请求,并将解析结果返回
-//url string hessian 服务地址
 //method string hessian 公开的方法
-//params ...Any 请求参数
-func Request(url string, method string, params ...Any) (interface{}, error) {
+//args ...Any 请求参数
+//服务端返回的 fault 以 *Fault 的形式返回
+func (this *Client) Invoke(method string, args ...Any) (interface{}, error) {
 	r := &hessianRequest{}
-	r.packHead(method)
-	for _, v := range params {
+	r.packHead(this.mangle(method, args))
+	for _, v := range args {
 		r.packParam(v)
 	}
 	r.packEnd()
 
-	resp, err := httpPost(url, bytes.NewReader(r.body))
+	resp, err := httpPost(this.url, bytes.NewReader(r.body))
 	if err != nil {
 		return nil, err
 	}
 
-	this := NewDecoder(resp)
-	v, err := this.Decode()
-
+	d := NewDecoder(resp)
+	d.SetPOJORegistry(this.pojos)
+	d.SetUnknownTypePolicy(this.unknown)
+	d.SetUnknownTypeHandler(this.handler)
+	v, err := d.Decode()
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +130,14 @@ func Request(url string, method string, params ...Any) (interface{}, error) {
 	return v, nil
 }
 
+//向hessian服务发请求,并将解析结果返回
+//url string hessian 服务地址
+//method string hessian 公开的方法
+//params ...Any 请求参数
+func Request(url string, method string, params ...Any) (interface{}, error) {
+	return NewClient(url).Invoke(method, params...)
+}
+
 // http post 请求, 返回body字节数组
 func httpPost(url string, body io.Reader) ([]byte, error) {
 	var (
@@ -12,7 +12,9 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const (
@@ -126,3 +128,71 @@ func TestException(t *testing.T) {
 	// DT(exception) = res: <nil> , err: NoSuchMethodException : The service has no method named: thorwException
 	fmt.Println("DT(exception) = res:", res, ", err:", err)
 }
+
+func TestOverload(t *testing.T) {
+	var (
+		err error
+		res interface{}
+		c   = NewClient("")
+	)
+
+	args := []Any{int32(1), int64(2), 3, 4.0, "5", true, []byte{6}, time.Now(), nil, []Any{}, map[Any]Any{}, Foo{}}
+	for mode, want := range map[OverloadMode]string{
+		OVERLOAD_NONE:  "add",
+		OVERLOAD_ARGC:  "add__12",
+		OVERLOAD_TYPES: "add_int_long_long_double_string_boolean_binary_date_Object_List_Map_Foo",
+	} {
+		c.SetOverload(mode)
+		if name := c.mangle("add", args); name != want {
+			t.Errorf("mangle(add) = %s, want %s", name, want)
+		}
+	}
+	// the names of the java classes by caucho AbstractSkeleton.mangleClass
+	for _, m := range []struct {
+		v    Any
+		want string
+	}{
+		{JavaShort(1), "int"},     // short
+		{[]Any{}, "List"},         // java.util.List
+		{NewOrderedMap(), "Map"},  // java.util.Map
+		{&TypedMap{}, "Map"},      // java.util.Map
+		{[]int64{}, "[long"},      // long[]
+		{[]string{}, "[string"},   // java.lang.String[]
+		{[][]int32{}, "[[int"},    // int[][]
+		{Account{}, "Account"},    // test.Account
+		{[]Account{}, "[Account"}, // test.Account[]
+	} {
+		if name := mangleValueType(m.v); name != m.want {
+			t.Errorf("mangleValueType(%#v) = %s, want %s", m.v, name, m.want)
+		}
+	}
+
+	c.SetOverload(OVERLOAD_ARGC)
+	if name := c.mangle("reset", nil); name != "reset__0" {
+		t.Errorf("mangle(reset) = %s", name)
+	}
+
+	s := NewServer()
+	s.Register("/math", &Math{})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c = NewClient(ts.URL + "/math")
+	for _, mode := range []OverloadMode{OVERLOAD_NONE, OVERLOAD_ARGC, OVERLOAD_TYPES} {
+		c.SetOverload(mode)
+		if res, err = c.Invoke("add", int32(100), int32(200)); err != nil || res != int32(300) {
+			t.Errorf("overload mode %d: add(100, 200) = res:%v, err:%v", mode, res, err)
+		}
+		if _, err = c.Invoke("reset"); err != nil {
+			t.Errorf("overload mode %d: reset() = err:%v", mode, err)
+		}
+		// the slices are mangled as the go server does
+		if res, err = c.Invoke("sum", []int64{1, 2}); err != nil || res != int64(3) {
+			t.Errorf("overload mode %d: sum([1, 2]) = res:%v, err:%v", mode, res, err)
+		}
+	}
+	// add(long, long) is not defined
+	if _, err = c.Invoke("add", 100, 200); err == nil {
+		t.Errorf("add_long_long(100, 200) should fail")
+	}
+}
@@ -0,0 +1,314 @@
+/******************************************************
+# DESC    : generate typed hessian client proxy of go interface
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 13:40
+# FILE    : main.go
+******************************************************/
+
+// hessiangen generates a client proxy of a go interface which describes a
+// hessian(java) service. Every method of the interface should return an
+// error as its last result, and at most one other result:
+//
+//	//go:generate hessiangen -type Math
+//	type Math interface {
+//		Add(a, b int32) (int32, error)
+//		// the name of the java method is "reset" rather than "Reset"
+//		//hessian:method reset
+//		Reset() error
+//	}
+//
+// The generated MathProxy implements Math by calling the hessian service with
+// hessian.Client, converting the results to the result types by
+// hessian.ConvertTo and returning the hessian fault as *hessian.Fault.
+//
+//	math := NewMathProxy(hessian.NewClient("http://localhost:8000/math"))
+//	sum, err := math.Add(1, 2)
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	HESSIAN_PKG      = "github.com/AlexStocks/gohessian"
+	METHOD_DIRECTIVE = "//hessian:method "
+)
+
+// the names used by the generated method
+var reserved = map[string]bool{"this": true, "rsp": true, "ret": true, "err": true, "hessian": true}
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of interface names; must be set")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_hessian.go")
+	input     = flag.String("file", os.Getenv("GOFILE"), "go source file which defines the interfaces; default $GOFILE")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of hessiangen:\n")
+	fmt.Fprintf(os.Stderr, "\thessiangen -type T [-file file.go] [-output file_hessian.go]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	var (
+		err error
+		src []byte
+		out []byte
+	)
+
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" || *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if src, err = ioutil.ReadFile(*input); err != nil {
+		fatalf("read %s: %v", *input, err)
+	}
+	if out, err = generate(*input, src, strings.Split(*typeNames, ",")); err != nil {
+		fatalf("%v", err)
+	}
+
+	if *output == "" {
+		name := strings.ToLower(strings.Split(*typeNames, ",")[0]) + "_hessian.go"
+		*output = filepath.Join(filepath.Dir(*input), name)
+	}
+	if err = ioutil.WriteFile(*output, out, 0644); err != nil {
+		fatalf("write %s: %v", *output, err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "hessiangen: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// a method of the interface
+type method struct {
+	name     string   // go method name
+	javaName string   // the method name sent to the hessian service
+	params   []string // name type
+	args     []string // parameter names
+	result   string   // the type of the non-error result, "" if there is not
+	pkgs     map[string]bool
+}
+
+// generate returns the formatted source of the proxies of @types defined in @src.
+func generate(filename string, src []byte, types []string) ([]byte, error) {
+	var (
+		err     error
+		fset    = token.NewFileSet()
+		file    *ast.File
+		buf     bytes.Buffer
+		imports = make(map[string]string) // package name -> import spec
+		used    = make(map[string]bool)
+		methods = make(map[string][]*method)
+	)
+
+	if file, err = parser.ParseFile(fset, filename, src, parser.ParseComments); err != nil {
+		return nil, err
+	}
+	for _, spec := range file.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = spec.Path.Value
+		if spec.Name != nil {
+			imports[name] = spec.Name.Name + " " + spec.Path.Value
+		}
+	}
+
+	for _, typ := range types {
+		var iface *ast.InterfaceType
+		if iface = findInterface(file, typ); iface == nil {
+			return nil, fmt.Errorf("interface %s is not found in %s", typ, filename)
+		}
+		for _, field := range iface.Methods.List {
+			var m *method
+			if m, err = parseMethod(fset, typ, field); err != nil {
+				return nil, err
+			}
+			for pkg := range m.pkgs {
+				used[pkg] = true
+			}
+			methods[typ] = append(methods[typ], m)
+		}
+	}
+
+	fmt.Fprintf(&buf, "// Code generated by hessiangen -type %s; DO NOT EDIT.\n\n", strings.Join(types, ","))
+	fmt.Fprintf(&buf, "package %s\n\n", file.Name.Name)
+	var specs []string
+	if file.Name.Name != "hessian" {
+		specs = append(specs, fmt.Sprintf("hessian %q", HESSIAN_PKG))
+	}
+	for pkg := range used {
+		if spec, ok := imports[pkg]; ok {
+			specs = append(specs, spec)
+		}
+	}
+	if len(specs) > 0 {
+		fmt.Fprintf(&buf, "import (\n\t%s\n)\n", strings.Join(specs, "\n\t"))
+	}
+
+	for _, typ := range types {
+		writeProxy(&buf, typ, methods[typ], file.Name.Name == "hessian")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	var iface *ast.InterfaceType
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == name {
+			iface, _ = spec.Type.(*ast.InterfaceType)
+			return false
+		}
+		return iface == nil
+	})
+
+	return iface
+}
+
+func parseMethod(fset *token.FileSet, typ string, field *ast.Field) (*method, error) {
+	var (
+		ok      bool
+		fn      *ast.FuncType
+		results []ast.Expr
+		m       = &method{pkgs: make(map[string]bool)}
+	)
+
+	if len(field.Names) == 0 {
+		return nil, fmt.Errorf("%s: embedded interface %s is not supported", typ, exprString(fset, field.Type))
+	}
+	m.name = field.Names[0].Name
+	m.javaName = m.name
+	if field.Doc != nil {
+		for _, c := range field.Doc.List {
+			if strings.HasPrefix(c.Text, METHOD_DIRECTIVE) {
+				m.javaName = strings.TrimSpace(strings.TrimPrefix(c.Text, METHOD_DIRECTIVE))
+			}
+		}
+	}
+
+	fn, ok = field.Type.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not a method", typ, m.name)
+	}
+	for i, param := range fn.Params.List {
+		var (
+			names []string
+			t     = exprString(fset, param.Type)
+		)
+		for _, name := range param.Names {
+			names = append(names, name.Name)
+		}
+		if len(names) == 0 {
+			names = append(names, fmt.Sprintf("arg%d", i))
+		}
+		for _, name := range names {
+			if reserved[name] {
+				return nil, fmt.Errorf("%s.%s: parameter name %s is reserved", typ, m.name, name)
+			}
+			m.params = append(m.params, name+" "+t)
+			m.args = append(m.args, name)
+		}
+		collectPackages(param.Type, m.pkgs)
+	}
+
+	if fn.Results != nil {
+		for _, result := range fn.Results.List {
+			n := len(result.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				results = append(results, result.Type)
+			}
+		}
+	}
+	if len(results) == 0 || len(results) > 2 || exprString(fset, results[len(results)-1]) != "error" {
+		return nil, fmt.Errorf("%s.%s should return (error) or (T, error)", typ, m.name)
+	}
+	if len(results) == 2 {
+		m.result = exprString(fset, results[0])
+		collectPackages(results[0], m.pkgs)
+	}
+
+	return m, nil
+}
+
+// collectPackages collects the package names referred by @expr.
+func collectPackages(expr ast.Expr, pkgs map[string]bool) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				pkgs[id.Name] = true
+			}
+		}
+		return true
+	})
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+func writeProxy(buf *bytes.Buffer, typ string, methods []*method, local bool) {
+	var (
+		pkg   = "hessian."
+		proxy = typ + "Proxy"
+	)
+
+	if local {
+		pkg = ""
+	}
+
+	fmt.Fprintf(buf, "\n// %s implements %s by calling the hessian service.\n", proxy, typ)
+	fmt.Fprintf(buf, "type %s struct {\n\tclient *%sClient\n}\n\n", proxy, pkg)
+	fmt.Fprintf(buf, "var _ %s = (*%s)(nil)\n\n", typ, proxy)
+	fmt.Fprintf(buf, "func New%s(client *%sClient) *%s {\n\treturn &%s{client: client}\n}\n", proxy, pkg, proxy, proxy)
+
+	for _, m := range methods {
+		var args = strings.Join(m.args, ", ")
+		if len(m.args) > 0 {
+			args = ", " + args
+		}
+
+		fmt.Fprintf(buf, "\nfunc (this *%s) %s(%s) ", proxy, m.name, strings.Join(m.params, ", "))
+		if m.result == "" {
+			fmt.Fprintf(buf, "error {\n")
+			fmt.Fprintf(buf, "\t_, err := this.client.Invoke(%q%s)\n", m.javaName, args)
+			fmt.Fprintf(buf, "\treturn err\n}\n")
+			continue
+		}
+
+		fmt.Fprintf(buf, "(%s, error) {\n", m.result)
+		fmt.Fprintf(buf, "\tvar ret %s\n", m.result)
+		fmt.Fprintf(buf, "\trsp, err := this.client.Invoke(%q%s)\n", m.javaName, args)
+		fmt.Fprintf(buf, "\tif err != nil {\n\t\treturn ret, err\n\t}\n")
+		fmt.Fprintf(buf, "\terr = %sConvertTo(rsp, &ret)\n", pkg)
+		fmt.Fprintf(buf, "\treturn ret, err\n}\n")
+	}
+}
@@ -0,0 +1,85 @@
+/******************************************************
+# DESC    : main.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 13:40
+# FILE    : main_test.go
+******************************************************/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// the proxy used by github.com/AlexStocks/gohessian/server_test.go is generated by hessiangen
+func TestGenerateGolden(t *testing.T) {
+	src, err := ioutil.ReadFile("../../server_test.go")
+	if err != nil {
+		t.Fatalf("ReadFile() = error:%v", err)
+	}
+	want, err := ioutil.ReadFile("../../mathservice_hessian_test.go")
+	if err != nil {
+		t.Fatalf("ReadFile() = error:%v", err)
+	}
+
+	out, err := generate("server_test.go", src, []string{"MathService"})
+	if err != nil {
+		t.Fatalf("generate() = error:%v", err)
+	}
+	if !bytes.Equal(out, want) {
+		t.Errorf("generate() = \n%s\nwant:\n%s", out, want)
+	}
+}
+
+const source = `package example
+
+import (
+	"time"
+
+	pb "github.com/foo/bar/proto"
+)
+
+type Echo interface {
+	EchoDate(d time.Time) (time.Time, error)
+	EchoUser(*pb.User, ...string) (map[string]*pb.User, error)
+	//hessian:method ping
+	Ping() error
+}
+
+type Bad interface {
+	Echo(s string) string
+}
+`
+
+func TestGenerate(t *testing.T) {
+	out, err := generate("echo.go", []byte(source), []string{"Echo"})
+	if err != nil {
+		t.Fatalf("generate() = error:%v", err)
+	}
+
+	for _, want := range []string{
+		`hessian "github.com/AlexStocks/gohessian"`,
+		`pb "github.com/foo/bar/proto"`,
+		`"time"`,
+		`func NewEchoProxy(client *hessian.Client) *EchoProxy {`,
+		`func (this *EchoProxy) EchoUser(arg0 *pb.User, arg1 ...string) (map[string]*pb.User, error) {`,
+		`rsp, err := this.client.Invoke("EchoUser", arg0, arg1)`,
+		`err = hessian.ConvertTo(rsp, &ret)`,
+		`_, err := this.client.Invoke("ping")`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("generate() = \n%s\nwant %s", out, want)
+		}
+	}
+
+	if _, err = generate("echo.go", []byte(source), []string{"Bad"}); err == nil {
+		t.Errorf("generate(Bad) should fail")
+	}
+	if _, err = generate("echo.go", []byte(source), []string{"Math"}); err == nil {
+		t.Errorf("generate(Math) should fail")
+	}
+}
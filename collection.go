@@ -0,0 +1,405 @@
+/******************************************************
+# DESC    : java collection type registry
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 18:30
+# FILE    : collection.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+const (
+	JAVA_HASH_MAP        = "java.util.HashMap"
+	JAVA_LINKED_HASH_MAP = "java.util.LinkedHashMap"
+	JAVA_TREE_MAP        = "java.util.TreeMap"
+	JAVA_HASH_SET        = "java.util.HashSet"
+	JAVA_ARRAY_LIST      = "java.util.ArrayList"
+)
+
+var (
+	collectionReg = CollectionRegistry{
+		javaTypes: make(map[string]reflect.Type),
+		goTypes:   make(map[reflect.Type]string),
+	}
+	orderedMapType = reflect.TypeOf((*OrderedMap)(nil))
+	typedMapType   = reflect.TypeOf(TypedMap{})
+)
+
+func init() {
+	RegisterJavaCollection(JAVA_HASH_MAP, map[Any]Any{})
+	RegisterJavaCollection(JAVA_ARRAY_LIST, []Any{})
+	RegisterJavaCollection(JAVA_TREE_MAP, TreeMap{})
+	RegisterJavaCollection(JAVA_HASH_SET, Set{})
+	RegisterJavaCollection(JAVA_LINKED_HASH_MAP, NewOrderedMap())
+}
+
+// Set is a java.util.HashSet, which is encoded as a typed list.
+type Set map[Any]struct{}
+
+func NewSet(items ...Any) Set {
+	var s = make(Set, len(items))
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+
+	return s
+}
+
+func (s Set) Add(item Any) {
+	s[item] = struct{}{}
+}
+
+func (s Set) Contains(item Any) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Items returns the items of the set in random order.
+func (s Set) Items() []Any {
+	var items = make([]Any, 0, len(s))
+	for item := range s {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// TreeMap is a java.util.TreeMap.
+type TreeMap map[Any]Any
+
+// OrderedMap is a map which keeps the insertion order of its keys, such as
+// java.util.LinkedHashMap. The encoder writes its entries in insertion order,
+// and the decoder keeps the order on the wire.
+type OrderedMap struct {
+	keys   []Any
+	values map[Any]Any
+}
+
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: make(map[Any]Any)}
+}
+
+// Set sets the value of @key. The position of @key is not changed if it exists.
+func (m *OrderedMap) Set(key Any, value Any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *OrderedMap) Get(key Any) (Any, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+func (m *OrderedMap) Delete(key Any) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap) Keys() []Any {
+	return append([]Any(nil), m.keys...)
+}
+
+// Range calls @f for each entry in insertion order until @f returns false.
+func (m *OrderedMap) Range(f func(key Any, value Any) bool) {
+	for _, k := range m.keys {
+		if !f(k, m.values[k]) {
+			break
+		}
+	}
+}
+
+// Map returns the entries as a go map.
+func (m *OrderedMap) Map() map[Any]Any {
+	var mm = make(map[Any]Any, len(m.values))
+	for k, v := range m.values {
+		mm[k] = v
+	}
+
+	return mm
+}
+
+// TypedMap is a map of java class Type which is not registered by
+// RegisterJavaCollection, such as a subclass of java.util.HashMap. Map is a
+// go map or a *OrderedMap, and it is encoded as a map of java class Type.
+// The decoders decode the map of an unregistered java class as *TypedMap
+// after SetTypedMap(true), so the java class is not lost.
+type TypedMap struct {
+	Type string
+	Map  Any
+}
+
+// CollectionRegistry maps the java collection classes to go types.
+type CollectionRegistry struct {
+	sync.RWMutex
+	javaTypes map[string]reflect.Type // decode: java class -> go type
+	goTypes   map[reflect.Type]string // encode: go type -> java class
+}
+
+// RegisterJavaCollection maps java collection class @javaType to the type of
+// @v, whose kind should be map or slice, or it is a *OrderedMap. A map whose
+// value type is struct{} or bool is a set, which is encoded as a list of its keys.
+// The map or list of type @javaType is decoded as the type of @v. The value of
+// the type of @v is encoded as a map or list of type @javaType if the type
+// is a named type, so map[Any]Any and []Any are still encoded as untyped.
+// *OrderedMap is encoded as java.util.LinkedHashMap.
+func RegisterJavaCollection(javaType string, v Any) error {
+	var typ = reflect.TypeOf(v)
+
+	if typ == nil || (typ.Kind() != reflect.Map && typ.Kind() != reflect.Slice && typ != orderedMapType) {
+		return fmt.Errorf("java collection %s should be a map or slice, but it is %T", javaType, v)
+	}
+
+	collectionReg.Lock()
+	collectionReg.javaTypes[javaType] = typ
+	if typ.Name() != "" || (typ == orderedMapType && javaType == JAVA_LINKED_HASH_MAP) {
+		collectionReg.goTypes[typ] = javaType
+	}
+	collectionReg.Unlock()
+
+	return nil
+}
+
+// getCollectionType returns the go type of java collection class @javaType.
+func getCollectionType(javaType string) (reflect.Type, bool) {
+	collectionReg.RLock()
+	typ, ok := collectionReg.javaTypes[javaType]
+	collectionReg.RUnlock()
+
+	return typ, ok
+}
+
+// getCollectionName returns the java collection class of go type @typ.
+func getCollectionName(typ reflect.Type) (string, bool) {
+	collectionReg.RLock()
+	name, ok := collectionReg.goTypes[typ]
+	collectionReg.RUnlock()
+
+	return name, ok
+}
+
+// isSetType checks whether @typ is a set, such as map[Any]struct{} and map[string]bool.
+func isSetType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Map {
+		return false
+	}
+
+	var elem = typ.Elem()
+	return elem.Kind() == reflect.Bool || (elem.Kind() == reflect.Struct && elem.NumField() == 0)
+}
+
+// isOrderedMap checks whether the map of java class @javaType should be decoded
+// as *OrderedMap. If @ordered is true, all the maps are decoded as *OrderedMap
+// except the ones whose java class is registered as a named go type, such as TreeMap.
+func isOrderedMap(javaType string, ordered bool) bool {
+	var typ, ok = getCollectionType(javaType)
+	if typ == orderedMapType {
+		return true
+	}
+	if isJavaObject(javaType) {
+		return false
+	}
+
+	return ordered && (!ok || typ.Name() == "")
+}
+
+// toCollection converts the decoded list or map @v of java class @javaType to
+// its registered go type, the go slice if @javaType is a java array such as
+// "[int", or the go value if @javaType is a java enum or it has a registered
+// serializer, see RegisterSerializer. @v is returned as it is if @javaType is
+// not registered or @v is a *OrderedMap, see isOrderedMap. The POJOs are
+// created by registry @pojos, or the global one if it is nil.
+func toCollection(v Any, javaType string, pojos *POJORegistry) (Any, error) {
+	if m, ok := v.(map[Any]Any); ok {
+		if e, ok, err := toJavaObject(m, javaType); ok {
+			return e, err
+		}
+	}
+
+	var typ, ok = getCollectionType(javaType)
+	if !ok {
+		// java array, such as "[int"
+		if typ, ok = getArrayType(javaType, pojos); !ok {
+			return v, nil
+		}
+	}
+	if _, ok = v.(*OrderedMap); ok {
+		return v, nil
+	}
+
+	rv, err := convertValue(v, typ)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", javaType, err)
+	}
+	// map[Any]Any is assignable to TreeMap
+	if rv.Type() != typ {
+		rv = rv.Convert(typ)
+	}
+
+	return rv.Interface(), nil
+}
+
+// collectionItems returns the items of the list or map @v.
+// The set is returned as a list of its keys. The set items and the map keys
+// are sorted by their bytes encoded by @enc if it is not nil.
+func collectionItems(v reflect.Value, enc func(Any) []byte) ([]Any, *OrderedMap) {
+	var (
+		list []Any
+		m    *OrderedMap
+	)
+
+	switch {
+	case v.Type() == orderedMapType:
+		if m = v.Interface().(*OrderedMap); m == nil {
+			m = NewOrderedMap()
+		}
+
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		list = make([]Any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list[i] = v.Index(i).Interface()
+		}
+
+	case isSetType(v.Type()):
+		list = make([]Any, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			if v.Type().Elem().Kind() == reflect.Bool && !v.MapIndex(key).Bool() {
+				continue
+			}
+			list = append(list, key.Interface())
+		}
+		sortItems(list, nil, enc)
+
+	default:
+		m = mapEntries(v, enc)
+	}
+
+	return list, m
+}
+
+// typedMapItems returns the entries of @v, whose keys are sorted by their
+// bytes encoded by @enc if it is not nil and @v.Map is a go map.
+func typedMapItems(v *TypedMap, enc func(Any) []byte) (*OrderedMap, error) {
+	if v.Map == nil {
+		return NewOrderedMap(), nil
+	}
+
+	var rv = reflect.ValueOf(v.Map)
+	if rv.Type() == orderedMapType {
+		if rv.IsNil() {
+			return NewOrderedMap(), nil
+		}
+		return v.Map.(*OrderedMap), nil
+	}
+	if rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("the map of java class %s should be a go map or *OrderedMap, but it is %T", v.Type, v.Map)
+	}
+
+	return mapEntries(rv, enc), nil
+}
+
+// isTypedMap checks whether the map of java class @javaType should be decoded
+// as *TypedMap, that is @typed is true and @javaType is not registered.
+func isTypedMap(javaType string, typed bool) bool {
+	if !typed || javaType == "" {
+		return false
+	}
+
+	var _, ok = getCollectionType(javaType)
+	if isJavaObject(javaType) {
+		return false
+	}
+	return !ok
+}
+
+// mapEntries returns the entries of go map @v as a *OrderedMap, whose keys
+// are sorted by their bytes encoded by @enc if it is not nil.
+func mapEntries(v reflect.Value, enc func(Any) []byte) *OrderedMap {
+	var (
+		m            = NewOrderedMap()
+		keys, values = mapItems(v)
+	)
+
+	sortItems(keys, values, enc)
+	for i := range keys {
+		m.Set(keys[i], values[i])
+	}
+
+	return m
+}
+
+// mapItems returns the keys and the values of map @v in random order.
+func mapItems(v reflect.Value) ([]Any, []Any) {
+	var (
+		keys   = make([]Any, 0, v.Len())
+		values = make([]Any, 0, v.Len())
+	)
+
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.Interface())
+		values = append(values, v.MapIndex(key).Interface())
+	}
+
+	return keys, values
+}
+
+// itemSorter sorts items by their encoded bytes, and moves the values
+// together with the items if there are values.
+type itemSorter struct {
+	items  []Any
+	values []Any
+	bytes  [][]byte
+}
+
+func (s itemSorter) Len() int {
+	return len(s.items)
+}
+
+func (s itemSorter) Less(i, j int) bool {
+	return bytes.Compare(s.bytes[i], s.bytes[j]) < 0
+}
+
+func (s itemSorter) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.bytes[i], s.bytes[j] = s.bytes[j], s.bytes[i]
+	if s.values != nil {
+		s.values[i], s.values[j] = s.values[j], s.values[i]
+	}
+}
+
+// sortItems sorts @items by their bytes encoded by @enc, the same items are
+// always in the same order whatever the order of the go map is. @values,
+// if it is not nil, is the values of @items. Nothing is done if @enc is nil.
+func sortItems(items []Any, values []Any, enc func(Any) []byte) {
+	if enc == nil || len(items) < 2 {
+		return
+	}
+
+	var s = itemSorter{items: items, values: values, bytes: make([][]byte, len(items))}
+	for i, item := range items {
+		s.bytes[i] = enc(item)
+	}
+	sort.Stable(s)
+}
@@ -0,0 +1,264 @@
+/******************************************************
+# DESC    : collection.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 18:30
+# FILE    : collection_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// go test -v -run TestCollection
+
+type LinkedList []Any
+
+type StringSet map[string]bool
+
+func init() {
+	RegisterJavaCollection("java.util.LinkedList", LinkedList{})
+	RegisterJavaCollection("java.util.TreeSet", StringSet{})
+}
+
+var collections = []struct {
+	v    Any
+	typ  string
+	list bool
+}{
+	{TreeMap{"a": int32(1)}, JAVA_TREE_MAP, false},
+	{NewSet("a", int64(2)), JAVA_HASH_SET, true},
+	{LinkedList{"a", int32(1), nil}, "java.util.LinkedList", true},
+	{StringSet{"a": true, "b": true}, "java.util.TreeSet", true},
+	{Set{}, JAVA_HASH_SET, true},
+}
+
+func TestCollectionEncode(t *testing.T) {
+	for _, c := range collections {
+		var (
+			b    = Encode(c.v, nil)
+			want = append([]byte{'M'}, encType(c.typ, nil)...)
+		)
+		if c.list {
+			want[0] = 'V'
+		}
+		if !bytes.HasPrefix(b, want) {
+			t.Errorf("Encode(%#v) = %s", c.v, SprintHex(b))
+			continue
+		}
+
+		v, err := NewDecoder(b).Decode()
+		if err != nil || !reflect.DeepEqual(v, c.v) {
+			t.Errorf("Decode(Encode(%#v)) = %#v, error:%v", c.v, v, err)
+		}
+	}
+
+	// the untyped and the default java collections
+	var cases = []struct {
+		b    []byte
+		want Any
+	}{
+		{append(append([]byte{'V'}, encType(JAVA_ARRAY_LIST, nil)...), 'l', 0, 0, 0, 1, 'T', 'z'), []Any{true}},
+		{append(append([]byte{'M'}, encType(JAVA_HASH_MAP, nil)...), 'T', 'F', 'z'), map[Any]Any{true: false}},
+		{append(append([]byte{'M'}, encType("java.util.Hashtable", nil)...), 'T', 'F', 'z'), map[Any]Any{true: false}},
+	}
+	for _, c := range cases {
+		v, err := NewDecoder(c.b).Decode()
+		if err != nil || !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decode(%s) = %#v, error:%v", SprintHex(c.b), v, err)
+		}
+	}
+	if b := Encode(map[Any]Any{true: false}, nil); b[1] == 't' {
+		t.Errorf("Encode(map[Any]Any) = %s", SprintHex(b))
+	}
+
+	// a list can not be an item of set
+	b := append(append([]byte{'V'}, encType(JAVA_HASH_SET, nil)...), 'l', 0, 0, 0, 1)
+	b = append(append(b, Encode([]Any{int32(1)}, nil)...), 'z')
+	if _, err := NewDecoder(b).Decode(); err == nil {
+		t.Errorf("Decode(HashSet of list) should fail")
+	}
+}
+
+func TestCollectionEncoder2(t *testing.T) {
+	var e = NewEncoder2()
+
+	for _, c := range collections {
+		e.Reset()
+		if err := e.Encode(c.v); err != nil {
+			t.Fatalf("Encode(%#v) = error:%v", c.v, err)
+		}
+		if b := e.Buffer(); !bytes.Contains(b[:len(c.typ)+3], []byte(c.typ)) {
+			t.Errorf("Encode(%#v) = %s", c.v, SprintHex(b))
+		}
+
+		v, err := NewDecoder2(e.Buffer()).Decode()
+		if err != nil || !reflect.DeepEqual(v, c.v) {
+			t.Errorf("Decode(Encode(%#v)) = %#v, error:%v", c.v, v, err)
+		}
+	}
+
+	// the reference is the converted value
+	e.Reset()
+	e.buffer = append(e.buffer, BC_LIST_DIRECT_UNTYPED+2)
+	e.Encode(LinkedList{"a"})
+	e.buffer = append(e.buffer, BC_REF, 0x91)
+	want := []Any{LinkedList{"a"}, LinkedList{"a"}}
+	v, err := NewDecoder2(e.Buffer()).Decode()
+	if err != nil || !reflect.DeepEqual(v, want) {
+		t.Errorf("Decode(list of LinkedList) = %#v, error:%v", v, err)
+	}
+}
+
+func TestRegisterJavaCollection(t *testing.T) {
+	if err := RegisterJavaCollection("java.util.Foo", 1); err == nil {
+		t.Errorf("RegisterJavaCollection(int) should fail")
+	}
+
+	var s Set
+	if err := ConvertTo([]Any{"a", "a", "b"}, &s); err != nil || len(s) != 2 || !s.Contains("b") {
+		t.Errorf("ConvertTo(Set) = %v, error:%v", s, err)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	var m = NewOrderedMap()
+
+	m.Set("z", int32(1))
+	m.Set("a", int32(2))
+	m.Set("m", int32(3))
+	m.Set("z", int32(4))
+	if !reflect.DeepEqual(m.Keys(), []Any{"z", "a", "m"}) || m.Len() != 3 {
+		t.Errorf("OrderedMap.Keys() = %v", m.Keys())
+	}
+	if v, ok := m.Get("z"); !ok || v != int32(4) {
+		t.Errorf("OrderedMap.Get(z) = %v, %v", v, ok)
+	}
+	m.Delete("a")
+	m.Delete("b")
+	var keys []Any
+	m.Range(func(k, v Any) bool {
+		keys = append(keys, k)
+		return false
+	})
+	if !reflect.DeepEqual(m.Keys(), []Any{"z", "m"}) || !reflect.DeepEqual(keys, []Any{"z"}) {
+		t.Errorf("OrderedMap.Keys() = %v, Range() = %v", m.Keys(), keys)
+	}
+
+	var mm map[string]int64
+	if err := ConvertTo(m, &mm); err != nil || !reflect.DeepEqual(mm, map[string]int64{"z": 4, "m": 3}) {
+		t.Errorf("ConvertTo(OrderedMap) = %v, error:%v", mm, err)
+	}
+	var om *OrderedMap
+	if err := ConvertTo(map[Any]Any{"a": 1}, &om); err != nil || om.Len() != 1 {
+		t.Errorf("ConvertTo(*OrderedMap) = %v, error:%v", om, err)
+	}
+}
+
+func TestOrderedMapEncode(t *testing.T) {
+	var m = NewOrderedMap()
+	for _, k := range []string{"z", "a", "m", "b"} {
+		m.Set(k, k)
+	}
+
+	// LinkedHashMap is decoded as *OrderedMap
+	b := Encode(m, nil)
+	want := append([]byte{'M'}, encType(JAVA_LINKED_HASH_MAP, nil)...)
+	for _, k := range []string{"z", "a", "m", "b"} {
+		want = encString(k, encString(k, want))
+	}
+	want = append(want, 'z')
+	if !bytes.Equal(b, want) {
+		t.Errorf("Encode(OrderedMap) = %s, want %s", SprintHex(b), SprintHex(want))
+	}
+	if v, err := NewDecoder(b).Decode(); err != nil || !reflect.DeepEqual(v, m) {
+		t.Errorf("Decode(LinkedHashMap) = %#v, error:%v", v, err)
+	}
+
+	e := NewEncoder2()
+	e.Encode(m)
+	if v, err := NewDecoder2(e.Buffer()).Decode(); err != nil || !reflect.DeepEqual(v, m) {
+		t.Errorf("Decoder2.Decode(LinkedHashMap) = %#v, error:%v", v, err)
+	}
+
+	// all the untyped maps are decoded as *OrderedMap
+	b = []byte{'M'}
+	e.Reset()
+	e.buffer = append(e.buffer, BC_MAP_UNTYPED)
+	for _, k := range []string{"z", "a", "m", "b"} {
+		b = encString(k, encString(k, b))
+		e.encString(k)
+		e.encString(k)
+	}
+	b = append(b, 'z')
+	e.buffer = append(e.buffer, BC_END)
+
+	d := NewDecoder(b)
+	d.SetOrderedMap(true)
+	if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, m) {
+		t.Errorf("Decode(map) = %#v, error:%v", v, err)
+	}
+	d2 := NewDecoder2(e.Buffer())
+	d2.SetOrderedMap(true)
+	if v, err := d2.Decode(); err != nil || !reflect.DeepEqual(v, m) {
+		t.Errorf("Decoder2.Decode(map) = %#v, error:%v", v, err)
+	}
+
+	// TreeMap is not affected
+	d = NewDecoder(Encode(TreeMap{"a": "a"}, nil))
+	d.SetOrderedMap(true)
+	if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, TreeMap{"a": "a"}) {
+		t.Errorf("Decode(TreeMap) = %#v, error:%v", v, err)
+	}
+}
+
+func TestTypedMap(t *testing.T) {
+	var (
+		tm   = TypedMap{Type: "com.foo.MyMap", Map: map[string]bool{"a": true}}
+		want = &TypedMap{Type: "com.foo.MyMap", Map: map[Any]Any{"a": true}}
+	)
+
+	b := Encode(tm, nil)
+	if !bytes.Equal(b, append(encString("a", append([]byte{'M'}, encType(tm.Type, nil)...)), 'T', 'z')) {
+		t.Errorf("Encode(%#v) = %s", tm, SprintHex(b))
+	}
+	if v, err := NewDecoder(b).Decode(); err != nil || !reflect.DeepEqual(v, map[Any]Any{"a": true}) {
+		t.Errorf("Decode(%s) = %#v, error:%v", SprintHex(b), v, err)
+	}
+	d := NewDecoder(b)
+	d.SetTypedMap(true)
+	if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, want) {
+		t.Errorf("Decode(%s) = %#v, error:%v", SprintHex(b), v, err)
+	}
+
+	e := NewEncoder2()
+	if err := e.Encode(&tm); err != nil {
+		t.Fatalf("Encoder2.Encode(%#v) = error:%v", tm, err)
+	}
+	d2 := NewDecoder2(e.Buffer())
+	d2.SetTypedMap(true)
+	if v, err := d2.Decode(); err != nil || !reflect.DeepEqual(v, want) {
+		t.Errorf("Decoder2.Decode(%s) = %#v, error:%v", SprintHex(e.Buffer()), v, err)
+	}
+
+	// the registered java collection is not affected
+	e.Reset()
+	e.Encode(TypedMap{Type: JAVA_TREE_MAP, Map: NewOrderedMap()})
+	d2 = NewDecoder2(e.Buffer())
+	d2.SetTypedMap(true)
+	d2.SetOrderedMap(true)
+	if v, err := d2.Decode(); err != nil || !reflect.DeepEqual(v, TreeMap{}) {
+		t.Errorf("Decoder2.Decode(TreeMap) = %#v, error:%v", v, err)
+	}
+
+	if b = Encode(TypedMap{Type: "com.foo.MyMap", Map: 1}, nil); b != nil {
+		t.Errorf("Encode(TypedMap of int) = %s", SprintHex(b))
+	}
+	if err := e.Encode(TypedMap{Type: "com.foo.MyMap", Map: 1}); err == nil {
+		t.Errorf("Encoder2.Encode(TypedMap of int) should fail")
+	}
+}
@@ -0,0 +1,227 @@
+/******************************************************
+# DESC    : convert decoded hessian values to go types
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 10:12
+# FILE    : convert.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// convertValue converts @v, which is a value returned by Decoder.Decode,
+// to a reflect.Value of type @typ.
+// Decode only produces int32/int64/float64/string/bool/[]byte/time.Time/
+// []Any/map[Any]Any/POJO, so numbers/lists/maps should be converted to the
+// parameter types of the go method or the result types of the caller.
+func convertValue(v Any, typ reflect.Type) (reflect.Value, error) {
+	var (
+		err error
+		ok  bool
+		rv  reflect.Value
+	)
+
+	if v == nil {
+		return reflect.Zero(typ), nil
+	}
+	if reflect.TypeOf(v).AssignableTo(typ) {
+		return reflect.ValueOf(v), nil
+	}
+	if rv, ok, err = unmarshal(v, typ); ok {
+		return rv, err
+	}
+	if rv, ok, err = convertBigNumber(v, typ); ok {
+		return rv, err
+	}
+	if rv, ok = convertTime(v, typ); ok {
+		return rv, nil
+	}
+	if rv, ok, err = convertJavaChar(v, typ); ok {
+		return rv, err
+	}
+
+	rv = reflect.ValueOf(v)
+	// ref('R') and list/map are stored as pointers in Decoder.refs
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.Type().AssignableTo(typ) {
+			return rv, nil
+		}
+		if rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Struct {
+			break
+		}
+		if rv.IsNil() {
+			return reflect.Zero(typ), nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Type().AssignableTo(typ) {
+		return rv, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if reflect.Zero(typ).OverflowInt(rv.Int()) {
+				break
+			}
+			return rv.Convert(typ), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return rv.Convert(typ), nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if rv.Int() < 0 || reflect.Zero(typ).OverflowUint(uint64(rv.Int())) {
+				break
+			}
+			return rv.Convert(typ), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return rv.Convert(typ), nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Float32, reflect.Float64:
+			return rv.Convert(typ), nil
+		}
+
+	case reflect.String:
+		if rv.Kind() == reflect.String {
+			return rv.Convert(typ), nil
+		}
+
+	case reflect.Bool:
+		if rv.Kind() == reflect.Bool {
+			return rv.Convert(typ), nil
+		}
+
+	case reflect.Slice:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			break
+		}
+		var s = reflect.MakeSlice(typ, rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			var e reflect.Value
+			if e, err = convertValue(rv.Index(i).Interface(), typ.Elem()); err != nil {
+				return rv, err
+			}
+			s.Index(i).Set(e)
+		}
+		return s, nil
+
+	case reflect.Array:
+		if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Len() > typ.Len() {
+			break
+		}
+		var a = reflect.New(typ).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			var e reflect.Value
+			if e, err = convertValue(rv.Index(i).Interface(), typ.Elem()); err != nil {
+				return rv, err
+			}
+			a.Index(i).Set(e)
+		}
+		return a, nil
+
+	case reflect.Map:
+		if om, ok := v.(*OrderedMap); ok {
+			rv = reflect.ValueOf(om.Map())
+		}
+		// java.util.Set is decoded as a list
+		if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && isSetType(typ) {
+			var (
+				m    = reflect.MakeMap(typ)
+				elem = reflect.New(typ.Elem()).Elem()
+			)
+			if typ.Elem().Kind() == reflect.Bool {
+				elem.SetBool(true)
+			}
+			for i := 0; i < rv.Len(); i++ {
+				var k reflect.Value
+				if k, err = convertValue(rv.Index(i).Interface(), typ.Key()); err != nil {
+					return rv, err
+				}
+				if !k.Type().Comparable() || (k.Kind() == reflect.Interface && k.Elem().IsValid() && !k.Elem().Type().Comparable()) {
+					return rv, fmt.Errorf("the item %T of set is not comparable", rv.Index(i).Interface())
+				}
+				m.SetMapIndex(k, elem)
+			}
+			return m, nil
+		}
+		if rv.Kind() != reflect.Map {
+			break
+		}
+		var m = reflect.MakeMap(typ)
+		for _, key := range rv.MapKeys() {
+			var k, e reflect.Value
+			if k, err = convertValue(key.Interface(), typ.Key()); err != nil {
+				return rv, err
+			}
+			if e, err = convertValue(rv.MapIndex(key).Interface(), typ.Elem()); err != nil {
+				return rv, err
+			}
+			m.SetMapIndex(k, e)
+		}
+		return m, nil
+
+	case reflect.Ptr:
+		if typ == orderedMapType && rv.Kind() == reflect.Map {
+			var om = NewOrderedMap()
+			for _, key := range rv.MapKeys() {
+				om.Set(key.Interface(), rv.MapIndex(key).Interface())
+			}
+			return reflect.ValueOf(om), nil
+		}
+		var e reflect.Value
+		if e, err = convertValue(v, typ.Elem()); err != nil {
+			return rv, err
+		}
+		var p = reflect.New(typ.Elem())
+		p.Elem().Set(e)
+		return p, nil
+
+	case reflect.Struct:
+		// a POJO is decoded as a pointer to struct
+		if rv.Kind() == reflect.Ptr && rv.Elem().Type().AssignableTo(typ) {
+			return rv.Elem(), nil
+		}
+	}
+
+	return rv, fmt.Errorf("can not convert %s to %s", rv.Type(), typ)
+}
+
+// ConvertTo converts @v, which is returned by Decoder.Decode or
+// Client.Invoke, to the type that @dst points to and stores it in @dst.
+//
+//	var sum int32
+//	res, err := client.Invoke("Add", 1, 2)
+//	err = ConvertTo(res, &sum)
+func ConvertTo(v Any, dst Any) error {
+	var (
+		err error
+		val reflect.Value
+		ptr = reflect.ValueOf(dst)
+	)
+
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return fmt.Errorf("@dst should be a non-nil pointer, but it is %T", dst)
+	}
+	if val, err = convertValue(v, ptr.Elem().Type()); err != nil {
+		return err
+	}
+	ptr.Elem().Set(val)
+
+	return nil
+}
@@ -18,15 +18,39 @@ import (
 )
 
 type Decoder struct {
-	reader *bufio.Reader
-	refs   []Any
+	reader  *bufio.Reader
+	refs    []Any
+	ordered bool // decode map as *OrderedMap
+	typed   bool // decode map of unregistered java class as *TypedMap
+	pojos   *POJORegistry
+	unknown UnknownTypePolicy
+	handler UnknownTypeHandler
 }
 
 var (
 	ErrNotEnoughBuf    = fmt.Errorf("not enough buf")
 	ErrIllegalRefIndex = fmt.Errorf("illegal ref index")
+	ErrIllegalCall     = fmt.Errorf("illegal call")
 )
 
+// checkMapKey checks whether the decoded map key @k can be a go map key, for
+// a list or map is a legal hessian map key but it is not comparable in go.
+func checkMapKey(k Any) error {
+	if k != nil && !reflect.TypeOf(k).Comparable() {
+		return fmt.Errorf("the map key %T is not comparable", k)
+	}
+
+	return nil
+}
+
+// hessian call
+// call ::= c x01 x00 header* m b16 b8 method-string (object)* z
+type Call struct {
+	Method  string
+	Headers map[string]Any
+	Args    []Any
+}
+
 // func NewDecoder(r io.Reader) *Decoder {
 // 	return &Decoder{reader: bufio.NewReader(r)}
 // }
@@ -39,6 +63,39 @@ func NewDecoder(b []byte) *Decoder {
 	return &Decoder{reader: bufio.NewReader(bytes.NewReader(b))}
 }
 
+// SetOrderedMap makes the decoder decode all the maps as *OrderedMap which
+// keeps the order on the wire, except the maps of the java classes registered
+// as named go types, such as java.util.TreeMap. java.util.LinkedHashMap is
+// always decoded as *OrderedMap.
+func (this *Decoder) SetOrderedMap(ordered bool) {
+	this.ordered = ordered
+}
+
+// SetTypedMap makes the decoder decode the map of java class which is neither
+// a registered POJO nor a registered java collection as *TypedMap, whose
+// Type is the java class. The untyped maps are not affected.
+func (this *Decoder) SetTypedMap(typed bool) {
+	this.typed = typed
+}
+
+// SetPOJORegistry makes the decoder create the POJOs of the java classes
+// registered in @r instead of the global registry of RegisterPOJO.
+func (this *Decoder) SetPOJORegistry(r *POJORegistry) {
+	this.pojos = r
+}
+
+// SetUnknownTypePolicy sets the way to decode the object or typed map of
+// unknown java class. SetTypedMap only works with UNKNOWN_TYPE_MAP.
+func (this *Decoder) SetUnknownTypePolicy(policy UnknownTypePolicy) {
+	this.unknown = policy
+}
+
+// SetUnknownTypeHandler makes the decoder decode the object or typed map of
+// unknown java class by @handler instead of the unknown type policy.
+func (this *Decoder) SetUnknownTypeHandler(handler UnknownTypeHandler) {
+	this.handler = handler
+}
+
 //读取当前字节,指针不前移
 func (this *Decoder) peekByte() byte {
 	return this.peek(1)[0]
@@ -105,6 +162,93 @@ func (this *Decoder) readType() string {
 	return string(this.nextRune(b)[3:]) //取类型名称
 }
 
+//读取 b16 b8 长度前缀的 utf8 字符串, 用于 call 的 method 和 header
+func (this *Decoder) readLenString() (string, error) {
+	var (
+		err error
+		l   int
+		s   = make([]byte, 2)
+	)
+
+	if l, err = this.next(s); err != nil {
+		return "", err
+	}
+	if l != 2 {
+		return "", ErrNotEnoughBuf
+	}
+
+	return string(this.nextRune(make([]rune, UnpackUint16(s)))), nil
+}
+
+//解析 hessian call 请求包
+func (this *Decoder) DecodeCall() (*Call, error) {
+	var (
+		err  error
+		t    byte
+		l    int
+		name string
+		arg  Any
+		call Call
+	)
+
+	if t, err = this.readByte(); err != nil {
+		return nil, err
+	}
+	if t != 'c' {
+		return nil, ErrIllegalCall
+	}
+	if l, err = this.next(make([]byte, 2)); err != nil {
+		return nil, err
+	}
+	if l != 2 {
+		return nil, ErrNotEnoughBuf
+	}
+
+	// header ::= H b16 b8 header-string object
+	for {
+		if t, err = this.readByte(); err != nil {
+			return nil, err
+		}
+		if t != 'H' {
+			break
+		}
+		if name, err = this.readLenString(); err != nil {
+			return nil, err
+		}
+		if arg, err = this.Decode(); err != nil {
+			return nil, err
+		}
+		if call.Headers == nil {
+			call.Headers = make(map[string]Any)
+		}
+		call.Headers[name] = arg
+	}
+
+	// method ::= m b16 b8 method-string
+	if t != 'm' {
+		return nil, ErrIllegalCall
+	}
+	if call.Method, err = this.readLenString(); err != nil {
+		return nil, err
+	}
+
+	for {
+		if len(this.peek(1)) == 0 {
+			return nil, ErrNotEnoughBuf
+		}
+		if this.peekByte() == 'z' {
+			this.readByte()
+			break
+		}
+		if arg, err = this.Decode(); err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+	}
+
+	return &call, nil
+}
+
 //解析 hessian 数据包
 func (this *Decoder) Decode() (interface{}, error) {
 	var (
@@ -234,9 +378,10 @@ func (this *Decoder) Decode() (interface{}, error) {
 	case 'V': //list
 		var (
 			v      Any
+			typ    string
 			chunks []Any
 		)
-		this.readType() // 忽略
+		typ = this.readType()
 		if this.peekByte() == byte('l') {
 			this.next(a[:5])
 		}
@@ -249,25 +394,67 @@ func (this *Decoder) Decode() (interface{}, error) {
 		}
 		this.readByte()
 		this.appendRefs(&chunks)
-		return chunks, nil
+		// the list of registered java collection class, such as java.util.HashSet
+		return toCollection(chunks, typ, this.pojos)
 
 	case 'M': //map
 		var (
-			k          Any
-			v          Any
-			t          string
-			keyName    string
-			methodName string
-			key        interface{}
-			value      interface{}
-			inst       interface{}
-			m          map[Any]Any
-			fieldValue reflect.Value
-			args       []reflect.Value
+			k    Any
+			v    Any
+			t    string
+			inst interface{}
+			m    map[Any]Any
+			om   *OrderedMap
 		)
 
 		t = this.readType()
-		if !checkPOJORegistry(t) {
+		// the registry is looked up once, for it may be changed concurrently
+		inst = getPOJORegistry(this.pojos).createInstance(t)
+		if inst == nil && isUnknownTypeObject(this.unknown, this.handler) && isUnknownType(t) {
+			var obj = NewObject(t)
+			for this.peekByte() != byte('z') {
+				if k, err = this.Decode(); err != nil {
+					return nil, err
+				}
+				if v, err = this.Decode(); err != nil {
+					return nil, err
+				}
+				if err = checkMapKey(k); err != nil {
+					return nil, err
+				}
+				obj.Fields.Set(k, v)
+			}
+			this.readByte()
+			if v, err = toUnknownType(obj, this.unknown, this.handler); err != nil {
+				return nil, err
+			}
+			this.appendRefs(v)
+			return v, nil
+
+		} else if inst == nil && isOrderedMap(t, this.ordered) {
+			om = NewOrderedMap()
+			for this.peekByte() != byte('z') {
+				if k, err = this.Decode(); err != nil {
+					return nil, err
+				}
+				if v, err = this.Decode(); err != nil {
+					return nil, err
+				}
+				if err = checkMapKey(k); err != nil {
+					return nil, err
+				}
+				om.Set(k, v)
+			}
+			this.readByte()
+			if isTypedMap(t, this.typed) {
+				var tm = &TypedMap{Type: t, Map: om}
+				this.appendRefs(tm)
+				return tm, nil
+			}
+			this.appendRefs(om)
+			return om, nil
+
+		} else if inst == nil {
 			m = make(map[Any]Any) // 此处假设了map的定义形式，这是不对的
 			// this.readType() // 忽略
 			for this.peekByte() != byte('z') {
@@ -283,57 +470,86 @@ func (this *Decoder) Decode() (interface{}, error) {
 				if err != nil {
 					return nil, err
 				}
+				if err = checkMapKey(k); err != nil {
+					return nil, err
+				}
 				m[k] = v
 			}
 			this.readByte()
+			if isTypedMap(t, this.typed) {
+				var tm = &TypedMap{Type: t, Map: m}
+				this.appendRefs(tm)
+				return tm, nil
+			}
 			this.appendRefs(&m)
-			return m, nil
+			// the map of registered java collection class, such as java.util.TreeMap
+			return toCollection(m, t, this.pojos)
 
 		} else {
-			fmt.Println("hello1")
-			inst = createInstance(t)
 			for this.peekByte() != 'z' {
-				if key, err = this.Decode(); err != nil {
-					fmt.Printf("key err:%#v", err)
+				if k, err = this.Decode(); err != nil {
 					return nil, err
 				}
-				fmt.Printf("key:%#v\n", key)
-				if value, err = this.Decode(); err != nil {
-					fmt.Printf("value err:%#v", err)
+				if v, err = this.Decode(); err != nil {
 					return nil, err
 				}
-				//set value of the struct to Zero
-				if fieldValue = reflect.ValueOf(value); fieldValue.IsValid() {
-					keyName = key.(string)
-					if keyName[0] >= 'a' { //convert to Upper
-						methodName = "Set" + string(keyName[0]-32) + keyName[1:]
-					} else {
-						methodName = "Set" + keyName
+				if name, ok := k.(string); ok {
+					if err = setPOJOField(inst, name, v); err != nil {
+						return nil, err
 					}
-
-					args = args[:0]
-					args = append(args, fieldValue)
-					fmt.Println("hello2")
-					reflect.ValueOf(inst).MethodByName(methodName).Call(args)
 				}
 			}
+			this.readByte()
 			// v = inst
 			this.appendRefs(&inst)
 			return inst, nil
 		}
 
 	case 'f': //fault
-		this.Decode() //drop "code"
-		code, _ := this.Decode()
-		this.Decode() //drop "message"
-		message, _ := this.Decode()
-		return nil, fmt.Errorf("%s : %s", code, message)
+		// fault ::= f (string object)* z
+		var (
+			key   Any
+			value Any
+			fault Fault
+		)
+		for {
+			if len(this.peek(1)) == 0 {
+				return nil, ErrNotEnoughBuf
+			}
+			if this.peekByte() == 'z' {
+				this.readByte()
+				break
+			}
+			if key, err = this.Decode(); err != nil {
+				return nil, err
+			}
+			if value, err = this.Decode(); err != nil {
+				return nil, err
+			}
+			switch key {
+			case "code":
+				fault.Code, _ = value.(string)
+			case "message":
+				fault.Message, _ = value.(string)
+			case "detail":
+				fault.Detail = value
+			}
+		}
+		return nil, &fault
 
 	case 'r': //reply
 		// valid-reply ::= r x01 x00 header* object z
 		// fault-reply ::= r x01 x00 header* fault z
 		this.next(a[:2])
-		return this.Decode()
+		var v, err = this.Decode()
+		if _, ok := err.(*Fault); err != nil && !ok {
+			return nil, err
+		}
+		// the terminator of the reply, so the following value can be decoded
+		if b := this.peek(1); len(b) == 1 && b[0] == 'z' {
+			this.readByte()
+		}
+		return v, err
 
 	case 'R': //ref, 一个整数，用以指代前面的list 或者 map
 		s = a[:4]
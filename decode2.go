@@ -0,0 +1,821 @@
+/******************************************************
+# DESC    : hessian 2.0 decode
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 15:10
+# FILE    : decode2.go
+******************************************************/
+
+// refers to http://hessian.caucho.com/doc/hessian-serialization.html
+// and com.caucho.hessian.io.Hessian2Input
+
+package hessian
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+	"unicode/utf16"
+)
+
+var (
+	ErrIllegalClassIndex = fmt.Errorf("illegal class definition index")
+	ErrIllegalTypeIndex  = fmt.Errorf("illegal type index")
+	ErrIllegalUTF8       = fmt.Errorf("illegal utf8 encoding")
+	ErrIllegalLength     = fmt.Errorf("illegal length")
+)
+
+// class definition
+type classDef struct {
+	typ    string
+	fields []string
+}
+
+// Decoder2 decodes hessian 2.0 values.
+// The class definitions, types and references are shared by all the values
+// decoded by a Decoder2, so it should decode the values of one hessian
+// stream(such as the body of a dubbo packet) in order.
+type Decoder2 struct {
+	source  *bytes.Reader
+	reader  *bufio.Reader
+	refs    []Any
+	classes []classDef
+	types   []string
+	ordered bool // decode map as *OrderedMap
+	typed   bool // decode map of unregistered java class as *TypedMap
+	pojos   *POJORegistry
+	unknown UnknownTypePolicy
+	handler UnknownTypeHandler
+}
+
+func NewDecoder2(b []byte) *Decoder2 {
+	var source = bytes.NewReader(b)
+	return &Decoder2{source: source, reader: bufio.NewReader(source)}
+}
+
+// SetOrderedMap is the same as Decoder.SetOrderedMap.
+func (this *Decoder2) SetOrderedMap(ordered bool) {
+	this.ordered = ordered
+}
+
+// SetTypedMap is the same as Decoder.SetTypedMap.
+func (this *Decoder2) SetTypedMap(typed bool) {
+	this.typed = typed
+}
+
+// SetPOJORegistry is the same as Decoder.SetPOJORegistry.
+func (this *Decoder2) SetPOJORegistry(r *POJORegistry) {
+	this.pojos = r
+}
+
+// SetUnknownTypePolicy is the same as Decoder.SetUnknownTypePolicy.
+func (this *Decoder2) SetUnknownTypePolicy(policy UnknownTypePolicy) {
+	this.unknown = policy
+}
+
+// SetUnknownTypeHandler is the same as Decoder.SetUnknownTypeHandler.
+func (this *Decoder2) SetUnknownTypeHandler(handler UnknownTypeHandler) {
+	this.handler = handler
+}
+
+//读取当前字节,指针不前移
+func (this *Decoder2) peekByte() (byte, error) {
+	var b, err = this.reader.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+//读取 Decoder2 结构中的一个字节,并后移一个字节
+func (this *Decoder2) readByte() (byte, error) {
+	return this.reader.ReadByte()
+}
+
+//读取指定长度的字节,并后移len(b)个字节
+func (this *Decoder2) next(b []byte) error {
+	var _, err = io.ReadFull(this.reader, b)
+	if err == io.ErrUnexpectedEOF {
+		return ErrNotEnoughBuf
+	}
+
+	return err
+}
+
+func (this *Decoder2) readInt16() (int16, error) {
+	var b [2]byte
+	if err := this.next(b[:]); err != nil {
+		return 0, err
+	}
+
+	return UnpackInt16(b[:]), nil
+}
+
+func (this *Decoder2) readInt32() (int32, error) {
+	var b [4]byte
+	if err := this.next(b[:]); err != nil {
+		return 0, err
+	}
+
+	return UnpackInt32(b[:]), nil
+}
+
+func (this *Decoder2) readInt64() (int64, error) {
+	var b [8]byte
+	if err := this.next(b[:]); err != nil {
+		return 0, err
+	}
+
+	return UnpackInt64(b[:]), nil
+}
+
+// readLength reads the count of the list items or the class fields, each of
+// which takes one byte at least, so it can not exceed the bytes left.
+func (this *Decoder2) readLength() (int, error) {
+	var n, err = this.readInt()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || n > this.source.Len()+this.reader.Buffered() {
+		return 0, ErrIllegalLength
+	}
+
+	return n, nil
+}
+
+// readInt reads an int which is used as length or index.
+func (this *Decoder2) readInt() (int, error) {
+	var v, err = this.Decode()
+	if err != nil {
+		return 0, err
+	}
+	if i, ok := v.(int32); ok {
+		return int(i), nil
+	}
+
+	return 0, fmt.Errorf("expect int, but got %T", v)
+}
+
+// readString reads a string which is used as type or field name.
+func (this *Decoder2) readString() (string, error) {
+	var v, err = this.Decode()
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	return "", fmt.Errorf("expect string, but got %T", v)
+}
+
+//读取 @n 个 java char(utf16), 参见 appendJavaChars
+func (this *Decoder2) nextChars(n int, chars []uint16) ([]uint16, error) {
+	var (
+		err error
+		c   byte
+		b   [3]byte
+		r   rune
+	)
+
+	for i := 0; i < n; i++ {
+		if c, err = this.readByte(); err != nil {
+			return nil, err
+		}
+		switch {
+		case c < 0x80:
+			chars = append(chars, uint16(c))
+		case c&0xe0 == 0xc0:
+			if err = this.next(b[:1]); err != nil {
+				return nil, err
+			}
+			chars = append(chars, uint16(c&0x1f)<<6|uint16(b[0]&0x3f))
+		case c&0xf0 == 0xe0:
+			if err = this.next(b[:2]); err != nil {
+				return nil, err
+			}
+			chars = append(chars, uint16(c&0x0f)<<12|uint16(b[0]&0x3f)<<6|uint16(b[1]&0x3f))
+		case c&0xf8 == 0xf0:
+			// a standard 4-byte utf8 char is two java chars
+			if err = this.next(b[:3]); err != nil {
+				return nil, err
+			}
+			r = rune(c&0x07)<<18 | rune(b[0]&0x3f)<<12 | rune(b[1]&0x3f)<<6 | rune(b[2]&0x3f)
+			r1, r2 := utf16.EncodeRune(r)
+			chars = append(chars, uint16(r1), uint16(r2))
+			i++
+		default:
+			return nil, ErrIllegalUTF8
+		}
+	}
+
+	return chars, nil
+}
+
+//读取数据类型描述,用于 list 和 map
+// type ::= string
+//      ::= int
+func (this *Decoder2) readType() (string, error) {
+	var (
+		err error
+		v   Any
+	)
+
+	if v, err = this.Decode(); err != nil {
+		return "", err
+	}
+	switch v.(type) {
+	case string:
+		this.types = append(this.types, v.(string))
+		return v.(string), nil
+	case int32:
+		if idx := int(v.(int32)); 0 <= idx && idx < len(this.types) {
+			return this.types[idx], nil
+		}
+		return "", ErrIllegalTypeIndex
+	}
+
+	return "", fmt.Errorf("illegal type %T", v)
+}
+
+//解析 hessian 2.0 数据
+func (this *Decoder2) Decode() (Any, error) {
+	var (
+		err error
+		t   byte
+		b   [3]byte
+	)
+
+	if t, err = this.readByte(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t == BC_NULL:
+		return nil, nil
+
+	case t == BC_TRUE:
+		return true, nil
+
+	case t == BC_FALSE:
+		return false, nil
+
+	// int
+	case 0x80 <= t && t <= 0xbf:
+		return int32(t) - int32(BC_INT_ZERO), nil
+
+	case 0xc0 <= t && t <= 0xcf:
+		if err = this.next(b[:1]); err != nil {
+			return nil, err
+		}
+		return (int32(t)-int32(BC_INT_BYTE_ZERO))<<8 + int32(b[0]), nil
+
+	case 0xd0 <= t && t <= 0xd7:
+		if err = this.next(b[:2]); err != nil {
+			return nil, err
+		}
+		return (int32(t)-int32(BC_INT_SHORT_ZERO))<<16 + int32(b[0])<<8 + int32(b[1]), nil
+
+	case t == BC_INT:
+		return this.readInt32()
+
+	// long
+	case 0xd8 <= t && t <= 0xef:
+		return int64(t) - int64(BC_LONG_ZERO), nil
+
+	case 0xf0 <= t: // t <= 0xff
+		if err = this.next(b[:1]); err != nil {
+			return nil, err
+		}
+		return (int64(t)-int64(BC_LONG_BYTE_ZERO))<<8 + int64(b[0]), nil
+
+	case 0x38 <= t && t <= 0x3f:
+		if err = this.next(b[:2]); err != nil {
+			return nil, err
+		}
+		return (int64(t)-int64(BC_LONG_SHORT_ZERO))<<16 + int64(b[0])<<8 + int64(b[1]), nil
+
+	case t == BC_LONG_INT:
+		i, err := this.readInt32()
+		return int64(i), err
+
+	case t == BC_LONG:
+		return this.readInt64()
+
+	// double
+	case t == BC_DOUBLE_ZERO:
+		return float64(0), nil
+
+	case t == BC_DOUBLE_ONE:
+		return float64(1), nil
+
+	case t == BC_DOUBLE_BYTE:
+		if err = this.next(b[:1]); err != nil {
+			return nil, err
+		}
+		return float64(int8(b[0])), nil
+
+	case t == BC_DOUBLE_SHORT:
+		s, err := this.readInt16()
+		return float64(s), err
+
+	case t == BC_DOUBLE_MILL:
+		mills, err := this.readInt32()
+		return 0.001 * float64(mills), err
+
+	case t == BC_DOUBLE:
+		l, err := this.readInt64()
+		return math.Float64frombits(uint64(l)), err
+
+	// date
+	case t == BC_DATE:
+		ms, err := this.readInt64()
+		return time.Unix(ms/1000, ms%1000*1e6), err
+
+	case t == BC_DATE_MINUTE:
+		minutes, err := this.readInt32()
+		return time.Unix(int64(minutes)*60, 0), err
+
+	// string
+	case t <= STRING_DIRECT_MAX, BC_STRING_SHORT <= t && t <= 0x33, t == BC_STRING, t == BC_STRING_CHUNK:
+		return this.decString(t)
+
+	// binary
+	case BC_BINARY_DIRECT <= t && t <= 0x2f, BC_BINARY_SHORT <= t && t <= 0x37, t == BC_BINARY, t == BC_BINARY_CHUNK:
+		return this.decBinary(t)
+
+	// list
+	case t == BC_LIST_FIXED, t == BC_LIST_VARIABLE, t == BC_LIST_FIXED_UNTYPED, t == BC_LIST_VARIABLE_UNTYPED,
+		BC_LIST_DIRECT <= t && t <= 0x7f:
+		return this.decList(t)
+
+	// map
+	case t == BC_MAP, t == BC_MAP_UNTYPED:
+		return this.decMap(t)
+
+	// object
+	case t == BC_OBJECT_DEF:
+		if err = this.decClassDef(); err != nil {
+			return nil, err
+		}
+		return this.Decode()
+
+	case t == BC_OBJECT, BC_OBJECT_DIRECT <= t && t <= 0x6f:
+		return this.decObject(t)
+
+	// ref ::= x51 int
+	case t == BC_REF:
+		idx, err := this.readInt()
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 || len(this.refs) <= idx {
+			return nil, ErrIllegalRefIndex
+		}
+		return this.refs[idx], nil
+	}
+
+	return nil, fmt.Errorf("Invalid type: 0x%02x", t)
+}
+
+// string ::= x52 b1 b0 <utf8-data> string
+//        ::= S b1 b0 <utf8-data>
+//        ::= [x00-x1f] <utf8-data>
+//        ::= [x30-x33] b0 <utf8-data>
+func (this *Decoder2) decString(t byte) (string, error) {
+	var (
+		err   error
+		l     int
+		b     [2]byte
+		chars []uint16
+	)
+
+	for {
+		switch {
+		case t <= STRING_DIRECT_MAX:
+			l = int(t - BC_STRING_DIRECT)
+		case BC_STRING_SHORT <= t && t <= 0x33:
+			if err = this.next(b[:1]); err != nil {
+				return "", err
+			}
+			l = int(t-BC_STRING_SHORT)<<8 + int(b[0])
+		case t == BC_STRING || t == BC_STRING_CHUNK:
+			if err = this.next(b[:2]); err != nil {
+				return "", err
+			}
+			l = int(UnpackUint16(b[:]))
+		default:
+			return "", fmt.Errorf("illegal string chunk 0x%02x", t)
+		}
+
+		if chars, err = this.nextChars(l, chars); err != nil {
+			return "", err
+		}
+		if t != BC_STRING_CHUNK {
+			break
+		}
+		if t, err = this.readByte(); err != nil {
+			return "", err
+		}
+	}
+
+	return string(utf16.Decode(chars)), nil
+}
+
+// binary ::= x41 b1 b0 <binary-data> binary
+//        ::= x42 b1 b0 <binary-data>
+//        ::= [x20-x2f] <binary-data>
+//        ::= [x34-x37] b0 <binary-data>
+func (this *Decoder2) decBinary(t byte) ([]byte, error) {
+	var (
+		err    error
+		l      int
+		b      [2]byte
+		chunks = []byte{}
+	)
+
+	for {
+		switch {
+		case BC_BINARY_DIRECT <= t && t <= 0x2f:
+			l = int(t - BC_BINARY_DIRECT)
+		case BC_BINARY_SHORT <= t && t <= 0x37:
+			if err = this.next(b[:1]); err != nil {
+				return nil, err
+			}
+			l = int(t-BC_BINARY_SHORT)<<8 + int(b[0])
+		case t == BC_BINARY || t == BC_BINARY_CHUNK:
+			if err = this.next(b[:2]); err != nil {
+				return nil, err
+			}
+			l = int(UnpackUint16(b[:]))
+		default:
+			return nil, fmt.Errorf("illegal binary chunk 0x%02x", t)
+		}
+
+		var chunk = make([]byte, l)
+		if err = this.next(chunk); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk...)
+		if t != BC_BINARY_CHUNK {
+			break
+		}
+		if t, err = this.readByte(); err != nil {
+			return nil, err
+		}
+	}
+
+	return chunks, nil
+}
+
+// list ::= x55 type value* 'Z'   # variable-length list
+//      ::= 'V' type int value*   # fixed-length list
+//      ::= x57 value* 'Z'        # variable-length untyped list
+//      ::= x58 int value*        # fixed-length untyped list
+//      ::= [x70-77] type value*  # fixed-length typed list
+//      ::= [x78-7f] value*       # fixed-length untyped list
+func (this *Decoder2) decList(t byte) (Any, error) {
+	var (
+		err    error
+		length = -1
+		v      Any
+		idx    int
+		typ    string
+		list   []Any
+	)
+
+	if t == BC_LIST_FIXED || t == BC_LIST_VARIABLE || (BC_LIST_DIRECT <= t && t < BC_LIST_DIRECT_UNTYPED) {
+		if typ, err = this.readType(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case t == BC_LIST_FIXED || t == BC_LIST_FIXED_UNTYPED:
+		if length, err = this.readLength(); err != nil {
+			return nil, err
+		}
+	case BC_LIST_DIRECT <= t && t < BC_LIST_DIRECT_UNTYPED:
+		length = int(t - BC_LIST_DIRECT)
+	case BC_LIST_DIRECT_UNTYPED <= t:
+		length = int(t - BC_LIST_DIRECT_UNTYPED)
+	}
+
+	idx = len(this.refs)
+	if length >= 0 {
+		// the elements may refer to the list itself
+		list = make([]Any, length)
+		this.refs = append(this.refs, list)
+		for i := 0; i < length; i++ {
+			if list[i], err = this.Decode(); err != nil {
+				return nil, err
+			}
+		}
+		return this.toCollection(idx, list, typ)
+	}
+
+	list = []Any{}
+	this.refs = append(this.refs, list)
+	for {
+		if t, err = this.peekByte(); err != nil {
+			return nil, err
+		}
+		if t == BC_END {
+			this.readByte()
+			break
+		}
+		if v, err = this.Decode(); err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+
+	return this.toCollection(idx, list, typ)
+}
+
+// toCollection converts the list or map @v to the go type of java collection
+// class @typ, and replaces the reference of @v with the result.
+func (this *Decoder2) toCollection(idx int, v Any, typ string) (Any, error) {
+	var c, err = toCollection(v, typ, this.pojos)
+	if err != nil {
+		return nil, err
+	}
+	this.refs[idx] = c
+
+	return c, nil
+}
+
+// toUnknownType applies the unknown type policy or handler to @obj, and
+// replaces the reference of @obj with the result.
+func (this *Decoder2) toUnknownType(idx int, obj *Object) (Any, error) {
+	var v, err = toUnknownType(obj, this.unknown, this.handler)
+	if err != nil {
+		return nil, err
+	}
+	this.refs[idx] = v
+
+	return v, nil
+}
+
+// map ::= M type (value value)* Z  # key, value map pairs
+//     ::= H (value value)* Z       # untyped key, value
+func (this *Decoder2) decMap(t byte) (Any, error) {
+	var (
+		err  error
+		typ  string
+		k    Any
+		v    Any
+		m    map[Any]Any
+		om   *OrderedMap
+		obj  *Object
+		inst Any
+		idx  = len(this.refs)
+	)
+
+	if t == BC_MAP {
+		if typ, err = this.readType(); err != nil {
+			return nil, err
+		}
+	}
+
+	if inst = getPOJORegistry(this.pojos).createInstance(typ); inst != nil {
+		this.refs = append(this.refs, inst)
+	} else if isUnknownTypeObject(this.unknown, this.handler) && isUnknownType(typ) {
+		obj = NewObject(typ)
+		om = obj.Fields
+		this.refs = append(this.refs, obj)
+	} else if isOrderedMap(typ, this.ordered) {
+		om = NewOrderedMap()
+		this.refs = append(this.refs, om)
+	} else {
+		m = make(map[Any]Any)
+		this.refs = append(this.refs, m)
+	}
+	for {
+		if t, err = this.peekByte(); err != nil {
+			return nil, err
+		}
+		if t == BC_END {
+			this.readByte()
+			break
+		}
+		if k, err = this.Decode(); err != nil {
+			return nil, err
+		}
+		if v, err = this.Decode(); err != nil {
+			return nil, err
+		}
+		if inst == nil {
+			if err = checkMapKey(k); err != nil {
+				return nil, err
+			}
+		}
+		if om != nil {
+			om.Set(k, v)
+			continue
+		}
+		if inst == nil {
+			m[k] = v
+			continue
+		}
+		if name, ok := k.(string); ok {
+			if err = setPOJOField(inst, name, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if inst != nil {
+		return inst, nil
+	}
+	if obj != nil {
+		return this.toUnknownType(idx, obj)
+	}
+	if isTypedMap(typ, this.typed) {
+		var tm = &TypedMap{Type: typ, Map: m}
+		if om != nil {
+			tm.Map = om
+		}
+		this.refs[idx] = tm
+		return tm, nil
+	}
+	if om != nil {
+		return om, nil
+	}
+	return this.toCollection(idx, m, typ)
+}
+
+// class-def ::= 'C' string int string*
+func (this *Decoder2) decClassDef() error {
+	var (
+		err error
+		n   int
+		def classDef
+	)
+
+	if def.typ, err = this.readString(); err != nil {
+		return err
+	}
+	if n, err = this.readLength(); err != nil {
+		return err
+	}
+	def.fields = make([]string, n)
+	for i := 0; i < n; i++ {
+		if def.fields[i], err = this.readString(); err != nil {
+			return err
+		}
+	}
+	this.classes = append(this.classes, def)
+
+	return nil
+}
+
+// object ::= 'O' int value*
+//        ::= [x60-x6f] value*
+// The object is decoded as a POJO if its type has been registered,
+// otherwise as a map[Any]Any whose keys are the field names.
+func (this *Decoder2) decObject(t byte) (Any, error) {
+	var def, err = this.objectDef(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return this.decObjectFields(def)
+}
+
+// objectDef returns the class definition of the object whose tag is @t.
+func (this *Decoder2) objectDef(t byte) (classDef, error) {
+	var (
+		err error
+		idx int
+	)
+
+	if t == BC_OBJECT {
+		if idx, err = this.readInt(); err != nil {
+			return classDef{}, err
+		}
+	} else {
+		idx = int(t - BC_OBJECT_DIRECT)
+	}
+	if idx < 0 || len(this.classes) <= idx {
+		return classDef{}, ErrIllegalClassIndex
+	}
+
+	return this.classes[idx], nil
+}
+
+func (this *Decoder2) decObjectFields(def classDef) (Any, error) {
+	var (
+		err  error
+		v    Any
+		inst Any
+		m    map[Any]Any
+		obj  *Object
+		idx  = len(this.refs)
+	)
+
+	if inst = getPOJORegistry(this.pojos).createInstance(def.typ); inst != nil {
+		this.refs = append(this.refs, inst)
+	} else if isUnknownTypeObject(this.unknown, this.handler) && isUnknownType(def.typ) {
+		obj = NewObject(def.typ)
+		this.refs = append(this.refs, obj)
+	} else {
+		m = make(map[Any]Any, len(def.fields))
+		this.refs = append(this.refs, m)
+	}
+	for _, field := range def.fields {
+		if v, err = this.Decode(); err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			obj.Fields.Set(field, v)
+			continue
+		}
+		if inst == nil {
+			m[field] = v
+			continue
+		}
+		if err = setPOJOField(inst, field, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if inst != nil {
+		return inst, nil
+	}
+	if obj != nil {
+		return this.toUnknownType(idx, obj)
+	}
+	if e, ok, err := toJavaObject(m, def.typ); ok {
+		if err != nil {
+			return nil, err
+		}
+		this.refs[idx] = e
+		return e, nil
+	}
+	return m, nil
+}
+
+// decodeObject decodes a value like Decode, and returns its class name as
+// well if it is an object.
+func (this *Decoder2) decodeObject() (string, Any, error) {
+	var (
+		err error
+		t   byte
+		def classDef
+		v   Any
+	)
+
+	for {
+		if t, err = this.peekByte(); err != nil {
+			return "", nil, err
+		}
+		if t != BC_OBJECT_DEF {
+			break
+		}
+		this.readByte()
+		if err = this.decClassDef(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if t != BC_OBJECT && (t < BC_OBJECT_DIRECT || BC_OBJECT_DIRECT+OBJECT_DIRECT_MAX < t) {
+		v, err = this.Decode()
+		return "", v, err
+	}
+	this.readByte()
+	if def, err = this.objectDef(t); err != nil {
+		return "", nil, err
+	}
+	v, err = this.decObjectFields(def)
+
+	return def.typ, v, err
+}
+
+// setPOJOField sets field @name of @inst to @value by its "Set..." method.
+// The field without setter is ignored.
+func setPOJOField(inst Any, name string, value Any) error {
+	var (
+		err    error
+		ok     bool
+		setter pojoSetter
+		arg    reflect.Value
+	)
+
+	if inst == nil || name == "" || value == nil {
+		return nil
+	}
+	if setter, ok = getPOJOSetters(reflect.TypeOf(inst))[upperFirst(name)]; !ok {
+		return nil
+	}
+	if arg, err = convertValue(value, setter.arg); err != nil {
+		return fmt.Errorf("field %s: %s", name, err)
+	}
+	reflect.ValueOf(inst).Method(setter.index).Call([]reflect.Value{arg})
+
+	return nil
+}
@@ -0,0 +1,192 @@
+/******************************************************
+# DESC    : decode2.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 15:10
+# FILE    : decode2_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -v -run TestDecoder2
+
+func TestDecoder2Primitive(t *testing.T) {
+	var cases = []struct {
+		b    []byte
+		want Any
+	}{
+		{[]byte{'N'}, nil},
+		{[]byte{'T'}, true},
+		{[]byte{'F'}, false},
+		{[]byte{0x90}, int32(0)},
+		{[]byte{0x80}, int32(-16)},
+		{[]byte{0xbf}, int32(47)},
+		{[]byte{0xc0, 0x00}, int32(-2048)},
+		{[]byte{0xcf, 0xff}, int32(2047)},
+		{[]byte{0xd0, 0x00, 0x00}, int32(-262144)},
+		{[]byte{0xd7, 0xff, 0xff}, int32(262143)},
+		{[]byte{'I', 0x00, 0x04, 0x00, 0x00}, int32(262144)},
+		{[]byte{0xe0}, int64(0)},
+		{[]byte{0xd8}, int64(-8)},
+		{[]byte{0xf0, 0x00}, int64(-2048)},
+		{[]byte{0x38, 0x00, 0x00}, int64(-262144)},
+		{[]byte{0x59, 0x00, 0x04, 0x00, 0x00}, int64(262144)},
+		{[]byte{'L', 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}, int64(1) << 32},
+		{[]byte{0x5b}, 0.0},
+		{[]byte{0x5c}, 1.0},
+		{[]byte{0x5d, 0x80}, -128.0},
+		{[]byte{0x5e, 0x7f, 0xff}, 32767.0},
+		{[]byte{0x5f, 0x00, 0x00, 0x2f, 0xda}, 12.25},
+		{[]byte{0x00}, ""},
+		{[]byte{0x05, 'h', 'e', 'l', 'l', 'o'}, "hello"},
+		{[]byte{0x01, 0xc3, 0x83}, "Ã"},
+		{[]byte{0x02, 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}, "\U0001F600"},
+		{[]byte{0x20}, []byte{}},
+		{[]byte{0x23, 1, 2, 3}, []byte{1, 2, 3}},
+		{[]byte{0x7a, 0x90, 0x91}, []Any{int32(0), int32(1)}},
+		{[]byte{'V', 0x04, '[', 'i', 'n', 't', 0x92, 0x90, 0x91}, []int32{0, 1}},
+		{[]byte{0x72, 0x07, '[', 's', 't', 'r', 'i', 'n', 'g', 0x01, 'a', 0x01, 'b'}, []string{"a", "b"}},
+		{[]byte{0x57, 0x90, 0x91, 'Z'}, []Any{int32(0), int32(1)}},
+		{[]byte{'H', 0x91, 0x03, 'f', 'e', 'e', 'Z'}, map[Any]Any{int32(1): "fee"}},
+	}
+
+	for _, c := range cases {
+		v, err := NewDecoder2(c.b).Decode()
+		if err != nil {
+			t.Errorf("Decode(%s) = error:%v", SprintHex(c.b), err)
+			continue
+		}
+		if !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decode(%s) = %#v, want %#v", SprintHex(c.b), v, c.want)
+		}
+	}
+
+	date := []byte{0x4a, 0x00, 0x00, 0x00, 0xd0, 0x4b, 0x92, 0x84, 0xb8}
+	if v, err := NewDecoder2(date).Decode(); err != nil || !v.(time.Time).Equal(time.Date(1998, 5, 8, 9, 51, 31, 0, time.UTC)) {
+		t.Errorf("Decode(date) = %v, error:%v", v, err)
+	}
+	if _, err := NewDecoder2([]byte{0x05, 'h'}).Decode(); err == nil {
+		t.Errorf("Decode(truncated string) should fail")
+	}
+}
+
+func TestDecoder2RoundTrip(t *testing.T) {
+	var (
+		e     = NewEncoder2()
+		list  = []Any{"a", int64(1)}
+		cases = []Any{
+			strings.Repeat("中", CHUNK_SIZE+1),
+			bytes.Repeat([]byte{1}, CHUNK_SIZE*2+1),
+			[]Any{int32(1), "two", 3.5, nil, []Any{true}},
+			map[Any]Any{"key": map[Any]Any{int64(1): []byte("v")}},
+		}
+	)
+
+	for _, c := range cases {
+		e.Reset()
+		if err := e.Encode(c); err != nil {
+			t.Fatalf("Encode(%T) = error:%v", c, err)
+		}
+		v, err := NewDecoder2(e.Buffer()).Decode()
+		if err != nil || !reflect.DeepEqual(v, c) {
+			t.Errorf("Decode(Encode(%T)) = %T, error:%v", c, v, err)
+		}
+	}
+
+	b := []byte{0x7a, 0x01, 'a', 0xe1}
+	v, err := NewDecoder2(b).Decode()
+	if err != nil || !reflect.DeepEqual(v, list) {
+		t.Errorf("Decode(list) = %#v, error:%v", v, err)
+	}
+	// the second element is a reference of the first one(the outer list is ref 0)
+	d := NewDecoder2(append(append([]byte{0x7a}, b...), 0x51, 0x91))
+	if v, err = d.Decode(); err != nil {
+		t.Fatalf("Decode(list of list) = error:%v", err)
+	}
+	if l := v.([]Any); len(l) != 2 || !reflect.DeepEqual(l[0], list) || !reflect.DeepEqual(l[1], l[0]) {
+		t.Errorf("Decode(list of list) = %#v", v)
+	}
+}
+
+func TestDecoder2Object(t *testing.T) {
+	var (
+		e = NewEncoder2()
+		d *Decoder2
+	)
+
+	RegisterPOJO(Foo{})
+	e.Encode(Car{color: "red", model: "corvette"})
+	e.Encode(&Foo{bar: 100, baz: "baz"})
+	e.Encode(Car{color: "green", model: "civic"})
+
+	d = NewDecoder2(e.Buffer())
+	// Car is not registered
+	v, err := d.Decode()
+	if err != nil || !reflect.DeepEqual(v, map[Any]Any{"color": "red", "model": "corvette"}) {
+		t.Errorf("Decode(Car) = %#v, error:%v", v, err)
+	}
+	v, err = d.Decode()
+	if foo, ok := v.(*Foo); err != nil || !ok || foo.bar != 100 || foo.baz != "baz" {
+		t.Errorf("Decode(Foo) = %#v, error:%v", v, err)
+	}
+	v, err = d.Decode()
+	if err != nil || !reflect.DeepEqual(v, map[Any]Any{"color": "green", "model": "civic"}) {
+		t.Errorf("Decode(Car) = %#v, error:%v", v, err)
+	}
+
+	if _, err = NewDecoder2([]byte{0x61}).Decode(); err != ErrIllegalClassIndex {
+		t.Errorf("Decode(undefined class) = error:%v", err)
+	}
+}
+
+// go test -v -run TestDecoder2IllegalLength
+
+func TestDecoder2IllegalLength(t *testing.T) {
+	for _, b := range [][]byte{
+		{'C', 0x01, 'a', 0x8f},                   // class with -1 fields
+		{'C', 0x01, 'a', 0x93, 0x01, 'b'},        // class with more fields than bytes
+		{0x58, 'I', 0x7f, 0xff, 0xff, 0xff},      // untyped list of 2^31-1 items
+		{'V', 0x04, '[', 'i', 'n', 't', 0x8e, 1}, // typed list of -2 items
+	} {
+		if v, err := NewDecoder2(b).Decode(); err != ErrIllegalLength {
+			t.Errorf("Decode(%s) = %#v, error:%v", SprintHex(b), v, err)
+		}
+	}
+
+	// the list whose items fill the rest bytes
+	if v, err := NewDecoder2([]byte{0x58, 0x92, 0x91, 0x92}).Decode(); err != nil || !reflect.DeepEqual(v, []Any{int32(1), int32(2)}) {
+		t.Errorf("Decode(list) = %#v, error:%v", v, err)
+	}
+}
+
+// go test -v -run TestDecoder2MapKey
+
+func TestDecoder2MapKey(t *testing.T) {
+	// the map whose key is a list
+	var b = []byte{'H', 0x79, 0x91, 0x91, 'Z'}
+	for _, ordered := range []bool{false, true} {
+		d := NewDecoder2(b)
+		d.SetOrderedMap(ordered)
+		if v, err := d.Decode(); err == nil {
+			t.Errorf("Decode(ordered:%t, %s) = %#v, want error", ordered, SprintHex(b), v)
+		}
+	}
+
+	// the typed map of unknown java class whose key is a map
+	b = []byte{'M', 0x03, 'f', 'o', 'o', 'H', 'Z', 0x91, 'Z'}
+	for _, policy := range []UnknownTypePolicy{UNKNOWN_TYPE_MAP, UNKNOWN_TYPE_OBJECT} {
+		d := NewDecoder2(b)
+		d.SetUnknownTypePolicy(policy)
+		if v, err := d.Decode(); err == nil {
+			t.Errorf("Decode(policy:%d, %s) = %#v, want error", policy, SprintHex(b), v)
+		}
+	}
+}
@@ -284,6 +284,20 @@ func TestDecodeMap(t *testing.T) {
 	}
 }
 
+// go test -v -run TestDecodeMapKey
+
+func TestDecodeMapKey(t *testing.T) {
+	// the map whose key is a list
+	var b = []byte{'M', 'V', 'l', 0, 0, 0, 1, 'I', 0, 0, 0, 1, 'z', 'I', 0, 0, 0, 1, 'z'}
+	for _, ordered := range []bool{false, true} {
+		d := NewDecoder(b)
+		d.SetOrderedMap(ordered)
+		if v, err := d.Decode(); err == nil {
+			t.Errorf("Decode(ordered:%t, %s) = %#v, want error", ordered, SprintHex(b), v)
+		}
+	}
+}
+
 type Foo struct {
 	bar int
 	baz string
@@ -347,3 +361,51 @@ func TestDecodeStruct(t *testing.T) {
 		fmt.Printf("*Foo{%#v}\n", s.(*Foo))
 	}
 }
+
+func TestDecodeCall(t *testing.T) {
+	var (
+		err  error
+		call *Call
+		r    hessianRequest
+	)
+
+	RegisterPOJO(Foo{})
+	r.packHead("Echo")
+	r.packParam(int32(100))
+	r.packParam("hello")
+	r.packParam(&Foo{bar: 100, baz: "baz"})
+	r.packParam([]Any{int32(1), "2"})
+	r.packEnd()
+
+	if call, err = NewDecoder(r.body).DecodeCall(); err != nil {
+		t.Fatalf("DecodeCall() = error:%v", err)
+	}
+	if call.Method != "Echo" || len(call.Headers) != 0 || len(call.Args) != 4 {
+		t.Fatalf("DecodeCall() = %#v", call)
+	}
+	if call.Args[0] != int32(100) || call.Args[1] != "hello" {
+		t.Errorf("DecodeCall() args = %#v", call.Args)
+	}
+	if foo, ok := call.Args[2].(*Foo); !ok || foo.bar != 100 || foo.baz != "baz" {
+		t.Errorf("DecodeCall() args[2] = %#v", call.Args[2])
+	}
+	if l, ok := call.Args[3].([]Any); !ok || !reflect.DeepEqual(l, []Any{int32(1), "2"}) {
+		t.Errorf("DecodeCall() args[3] = %#v", call.Args[3])
+	}
+
+	// c x01 x00 H x00 x02 id I x00 x00 x00 x07 m x00 x03 add I x00 x00 x00 x01 z
+	b := []byte{'c', 1, 0, 'H', 0, 2, 'i', 'd', 'I', 0, 0, 0, 7, 'm', 0, 3, 'a', 'd', 'd', 'I', 0, 0, 0, 1, 'z'}
+	if call, err = NewDecoder(b).DecodeCall(); err != nil {
+		t.Fatalf("DecodeCall() = error:%v", err)
+	}
+	if call.Method != "add" || call.Headers["id"] != int32(7) || len(call.Args) != 1 || call.Args[0] != int32(1) {
+		t.Errorf("DecodeCall() = %#v", call)
+	}
+
+	if _, err = NewDecoder(append(REPLY, 'N')).DecodeCall(); err != ErrIllegalCall {
+		t.Errorf("DecodeCall(reply) = error:%v", err)
+	}
+	if _, err = NewDecoder(b[:len(b)-1]).DecodeCall(); err == nil {
+		t.Errorf("DecodeCall() without z should fail")
+	}
+}
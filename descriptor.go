@@ -0,0 +1,184 @@
+/******************************************************
+# DESC    : jvm type descriptor of dubbo invocation parameters
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 16:20
+# FILE    : descriptor.go
+******************************************************/
+
+// refers to com.alibaba.dubbo.common.utils.ReflectUtils.getDesc
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	JAVA_OBJECT_DESC = "Ljava/lang/Object;"
+	JAVA_STRING_DESC = "Ljava/lang/String;"
+	JAVA_DATE_DESC   = "Ljava/util/Date;"
+	JAVA_MAP_DESC    = "Ljava/util/Map;"
+	JAVA_LIST_DESC   = "Ljava/util/List;"
+)
+
+// the descriptor of the java primitive type of go basic kind
+var primitiveDesc = map[reflect.Kind]string{
+	reflect.Bool:    "Z",
+	reflect.Int8:    "B",
+	reflect.Int16:   "S",
+	reflect.Int32:   "I",
+	reflect.Int:     "J", // int is encoded as long
+	reflect.Int64:   "J",
+	reflect.Float32: "F",
+	reflect.Float64: "D",
+}
+
+// the descriptor of the boxed java type of go basic kind, used for pointers
+var boxedDesc = map[reflect.Kind]string{
+	reflect.Bool:    "Ljava/lang/Boolean;",
+	reflect.Int8:    "Ljava/lang/Byte;",
+	reflect.Int16:   "Ljava/lang/Short;",
+	reflect.Int32:   "Ljava/lang/Integer;",
+	reflect.Int:     "Ljava/lang/Long;",
+	reflect.Int64:   "Ljava/lang/Long;",
+	reflect.Float32: "Ljava/lang/Float;",
+	reflect.Float64: "Ljava/lang/Double;",
+	reflect.String:  JAVA_STRING_DESC,
+}
+
+// GetParamTypes returns the jvm type descriptor of @args, such as
+// "Ljava/lang/String;I[J" of ("hello", int32(1), []int64{1}).
+//
+// go type                        java type
+// bool, int8, int16, int32       boolean, byte, short, int
+// int, int64, float32, float64   long, long, float, double
+// *bool, *int32, *int64, ...     Boolean, Integer, Long, ...
+// string, []byte, time.Time      String, byte[], Date
+// []Any                          List
+// []T(such as []int64, []*Foo)   T[]
+// map                            Map
+// POJO                           the class returned by GetType()
+// *Object                        the class of Type
+// HessianMarshaler, Serializer   the serializer's java type or the marshaled value's
+// nil, interface                 Object
+func GetParamTypes(args ...Any) (string, error) {
+	var (
+		err   error
+		desc  string
+		types []string
+	)
+
+	for i, arg := range args {
+		if desc, err = GetTypeDesc(arg); err != nil {
+			return "", fmt.Errorf("args[%d]: %s", i, err)
+		}
+		types = append(types, desc)
+	}
+
+	return strings.Join(types, ""), nil
+}
+
+// GetTypeDesc returns the jvm type descriptor of @v.
+func GetTypeDesc(v Any) (string, error) {
+	if v == nil {
+		return JAVA_OBJECT_DESC, nil
+	}
+
+	typ := reflect.TypeOf(v)
+	if s, ok := getSerializer(typ); ok && s.javaType != "" {
+		return classDesc(s.javaType), nil
+	}
+	// the descriptor of the value to be encoded instead
+	if m, ok, err := marshal(v); ok {
+		if err != nil {
+			return "", err
+		}
+		return GetTypeDesc(m)
+	}
+	if p, ok := v.(POJO); ok {
+		return classDesc(p.GetType()), nil
+	}
+	if o, ok := v.(*Object); ok && o != nil {
+		return classDesc(o.Type), nil
+	}
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Interface {
+		return JAVA_LIST_DESC, nil
+	}
+
+	return typeDesc(typ)
+}
+
+func typeDesc(typ reflect.Type) (string, error) {
+	if typ == durationType {
+		return classDesc(JAVA_DURATION), nil
+	}
+	if e, ok := getJavaEnumOf(typ); ok {
+		return classDesc(e.javaType), nil
+	}
+	if s, ok := getSerializer(typ); ok && s.javaType != "" {
+		return classDesc(s.javaType), nil
+	}
+	// uint8 and uint16 can not be encoded, except []byte and JavaChar
+	switch typ {
+	case bytesType:
+		return "[B", nil
+	case javaCharType:
+		return "C", nil
+	case reflect.PtrTo(javaCharType):
+		return "Ljava/lang/Character;", nil
+	}
+	if desc, ok := primitiveDesc[typ.Kind()]; ok {
+		return desc, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return JAVA_STRING_DESC, nil
+	case reflect.Interface:
+		return JAVA_OBJECT_DESC, nil
+	case reflect.Map:
+		return JAVA_MAP_DESC, nil
+	case reflect.Slice, reflect.Array:
+		desc, err := typeDesc(typ.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "[" + desc, nil
+	case reflect.Struct, reflect.Ptr:
+		if typ == timeType {
+			return JAVA_DATE_DESC, nil
+		}
+		if typ == orderedMapType || typ == typedMapType || typ == reflect.PtrTo(typedMapType) {
+			return JAVA_MAP_DESC, nil
+		}
+		if typ == bigIntType {
+			return classDesc(JAVA_BIG_INTEGER), nil
+		}
+		if typ == bigFloatType {
+			return classDesc(JAVA_BIG_DECIMAL), nil
+		}
+		if typ.Implements(pojoType) {
+			return classDesc(getPOJOType(typ)), nil
+		}
+		if typ.Kind() == reflect.Ptr {
+			if desc, ok := boxedDesc[typ.Elem().Kind()]; ok {
+				return desc, nil
+			}
+			return typeDesc(typ.Elem())
+		}
+	}
+
+	return "", fmt.Errorf("no java type of %s", typ)
+}
+
+// classDesc converts the java class name to its descriptor.
+// "java.lang.String" -> "Ljava/lang/String;"
+func classDesc(class string) string {
+	if c, ok := javaHandleClasses[class]; ok {
+		class = c
+	}
+	return "L" + strings.Replace(class, ".", "/", -1) + ";"
+}
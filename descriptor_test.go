@@ -0,0 +1,78 @@
+/******************************************************
+# DESC    : descriptor.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 16:20
+# FILE    : descriptor_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+// go test -v -run TestGetParamTypes
+
+func TestGetParamTypes(t *testing.T) {
+	var (
+		i32 int32
+		f64 float64
+		s   string
+	)
+
+	var cases = []struct {
+		args []Any
+		want string
+	}{
+		{nil, ""},
+		{[]Any{"hello", int32(1), []int64{1}}, "Ljava/lang/String;I[J"},
+		{[]Any{true, int8(1), int16(1), 1, int64(1), float32(1), 1.0}, "ZBSJJFD"},
+		{[]Any{&i32, &f64, &s}, "Ljava/lang/Integer;Ljava/lang/Double;Ljava/lang/String;"},
+		{[]Any{nil, []byte{1}, time.Now()}, "Ljava/lang/Object;[BLjava/util/Date;"},
+		{[]Any{[]Any{1}, map[string]int32{}, map[Any]Any{}}, "Ljava/util/List;Ljava/util/Map;Ljava/util/Map;"},
+		{[]Any{[]string{}, [][]int32{}, []map[string]string{}}, "[Ljava/lang/String;[[I[Ljava/util/Map;"},
+		{[]Any{NewOrderedMap(), TypedMap{}, &TypedMap{}}, "Ljava/util/Map;Ljava/util/Map;Ljava/util/Map;"},
+		{[]Any{big.NewInt(1), big.NewFloat(1), BigDecimal{}}, "Ljava/math/BigInteger;Ljava/math/BigDecimal;Ljava/math/BigDecimal;"},
+		{[]Any{RED, LEVEL_INFO}, "Lcom/foo/Color;Lcom/foo/Level;"},
+		{[]Any{LocalDate{}, &ZonedDateTime{}, time.Second, Timestamp{}}, "Ljava/time/LocalDate;Ljava/time/ZonedDateTime;Ljava/time/Duration;Ljava/sql/Timestamp;"},
+		{[]Any{Car{}, &Car{}, []*Car{}, []Car{}}, "Lexample/Car;Lexample/Car;[Lexample/Car;[Lexample/Car;"},
+	}
+
+	for _, c := range cases {
+		desc, err := GetParamTypes(c.args...)
+		if err != nil || desc != c.want {
+			t.Errorf("GetParamTypes(%#v) = %q, error:%v, want %q", c.args, desc, err, c.want)
+			continue
+		}
+		if types, err := splitDescriptor(desc); err != nil || len(types) != len(c.args) {
+			t.Errorf("splitDescriptor(%q) = %v, error:%v", desc, types, err)
+		}
+	}
+
+	if _, err := GetParamTypes("a", make(chan int)); err == nil {
+		t.Errorf("GetParamTypes(chan) should fail")
+	}
+	// uint8 and uint16 can not be encoded
+	for _, v := range []Any{uint8(1), uint16(1), []uint16{1}, new(uint16)} {
+		if desc, err := GetTypeDesc(v); err == nil {
+			t.Errorf("GetTypeDesc(%T) = %s", v, desc)
+		}
+	}
+}
+
+func TestDubboRequestParamTypes(t *testing.T) {
+	b, err := EncodeDubboRequest(&DubboRequest{Path: "com.foo.Math", Method: "sum", Args: []Any{[]int32{1, 2}, &Car{}}})
+	if err != nil {
+		t.Fatalf("EncodeDubboRequest() = error:%v", err)
+	}
+	pkg, _, err := DecodeDubboPacket(b)
+	if err != nil {
+		t.Fatalf("DecodeDubboPacket() = error:%v", err)
+	}
+	if req := pkg.(*DubboRequest); req.ParamTypes != "[ILexample/Car;" || len(req.Args) != 2 {
+		t.Errorf("DecodeDubboPacket() = %#v", req)
+	}
+}
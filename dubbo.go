@@ -0,0 +1,629 @@
+/******************************************************
+# DESC    : dubbo protocol packet codec
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 15:10
+# FILE    : dubbo.go
+******************************************************/
+
+// refers to com.alibaba.dubbo.remoting.exchange.codec.ExchangeCodec
+// and com.alibaba.dubbo.rpc.protocol.dubbo.DubboCodec
+
+package hessian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// dubbo packet header
+// 0      2      3      4             12        16
+// +------+------+------+-------------+---------+
+// |magic | flag |status| request id  | body len|
+// +------+------+------+-------------+---------+
+const (
+	DUBBO_HEADER_LENGTH = 16
+	DUBBO_MAGIC         = uint16(0xdabb)
+	DUBBO_MAGIC_HIGH    = byte(0xda)
+	DUBBO_MAGIC_LOW     = byte(0xbb)
+
+	FLAG_REQUEST       = byte(0x80)
+	FLAG_TWOWAY        = byte(0x40)
+	FLAG_EVENT         = byte(0x20) // heartbeat
+	SERIALIZATION_MASK = byte(0x1f)
+
+	// com.alibaba.dubbo.common.serialize.support.hessian.Hessian2Serialization
+	HESSIAN2_SERIALIZATION_ID = byte(2)
+
+	// the max length of the body, 8M refers to Constants.DEFAULT_PAYLOAD
+	DUBBO_MAX_BODY_LENGTH = 8 * 1024 * 1024
+
+	DUBBO_VERSION         = "2.0.2"
+	DUBBO_SERVICE_VERSION = "0.0.0"
+)
+
+// response status, refers to com.alibaba.dubbo.remoting.exchange.Response
+const (
+	DUBBO_OK                                = byte(20)
+	DUBBO_CLIENT_TIMEOUT                    = byte(30)
+	DUBBO_SERVER_TIMEOUT                    = byte(31)
+	DUBBO_BAD_REQUEST                       = byte(40)
+	DUBBO_BAD_RESPONSE                      = byte(50)
+	DUBBO_SERVICE_NOT_FOUND                 = byte(60)
+	DUBBO_SERVICE_ERROR                     = byte(70)
+	DUBBO_SERVER_ERROR                      = byte(80)
+	DUBBO_CLIENT_ERROR                      = byte(90)
+	DUBBO_SERVER_THREADPOOL_EXHAUSTED_ERROR = byte(100)
+)
+
+// the type of the response body, refers to DubboCodec
+const (
+	RESPONSE_WITH_EXCEPTION                  = int32(0)
+	RESPONSE_VALUE                           = int32(1)
+	RESPONSE_NULL_VALUE                      = int32(2)
+	RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS = int32(3)
+	RESPONSE_VALUE_WITH_ATTACHMENTS          = int32(4)
+	RESPONSE_NULL_VALUE_WITH_ATTACHMENTS     = int32(5)
+)
+
+// the attachment keys
+const (
+	DUBBO_PATH_KEY      = "path"
+	DUBBO_INTERFACE_KEY = "interface"
+	DUBBO_VERSION_KEY   = "version"
+	DUBBO_GROUP_KEY     = "group"
+	DUBBO_TIMEOUT_KEY   = "timeout"
+)
+
+const (
+	// the java exception of the error returned by go provider
+	JAVA_RUNTIME_EXCEPTION = "java.lang.RuntimeException"
+)
+
+var (
+	ErrIllegalDubboMagic      = fmt.Errorf("illegal dubbo magic")
+	ErrIllegalDubboPacket     = fmt.Errorf("illegal dubbo packet")
+	ErrDubboHeaderNotEnough   = fmt.Errorf("dubbo header length is not enough")
+	ErrDubboBodyNotEnough     = fmt.Errorf("dubbo body length is not enough")
+	ErrDubboBodyTooLarge      = fmt.Errorf("dubbo body is too large")
+	ErrDubboSerializationType = fmt.Errorf("dubbo serialization is not hessian2")
+)
+
+// DubboHeader is the 16-byte header of a dubbo packet.
+type DubboHeader struct {
+	Request       bool
+	TwoWay        bool
+	Event         bool
+	Serialization byte
+	Status        byte // response only
+	ID            int64
+	BodyLen       int
+}
+
+// DubboDecodeOptions are the options of the hessian2 decoder of the dubbo
+// packet bodies. The zero value is the default decoder.
+type DubboDecodeOptions struct {
+	POJORegistry       *POJORegistry // the global registry of RegisterPOJO if nil
+	UnknownTypePolicy  UnknownTypePolicy
+	UnknownTypeHandler UnknownTypeHandler
+}
+
+// newDecoder returns the decoder of the dubbo packet body @body.
+func (this *DubboDecodeOptions) newDecoder(body []byte) *Decoder2 {
+	var d = NewDecoder2(body)
+	if this != nil {
+		d.SetPOJORegistry(this.POJORegistry)
+		d.SetUnknownTypePolicy(this.UnknownTypePolicy)
+		d.SetUnknownTypeHandler(this.UnknownTypeHandler)
+	}
+
+	return d
+}
+
+// DubboRequest is a dubbo invocation or a heartbeat(Event) request.
+type DubboRequest struct {
+	ID     int64
+	TwoWay bool
+	Event  bool // a heartbeat request has no invocation
+
+	DubboVersion string
+	Path         string // service path(interface name)
+	Version      string // service version
+	Method       string
+	ParamTypes   string // jvm type descriptor of the parameters, such as "Ljava/lang/String;I"; GetParamTypes(Args...) if empty
+	Args         []Any
+	Attachments  map[string]string
+}
+
+// DubboResponse is the response of a dubbo request.
+type DubboResponse struct {
+	ID     int64
+	Status byte
+	Event  bool // the response of a heartbeat
+
+	// status == DUBBO_OK
+	Result      Any
+	Exception   error // the exception thrown by provider, *DubboException if it is decoded
+	Attachments map[string]string
+	// status != DUBBO_OK
+	ErrorMessage string
+}
+
+// a java exception whose detailMessage is the go error
+type javaThrowable struct {
+	typ     string
+	message string
+}
+
+func (e javaThrowable) GetType() string {
+	return e.typ
+}
+
+func (e javaThrowable) GetDetailMessage() string {
+	return e.message
+}
+
+// DubboException is the java exception thrown by dubbo provider.
+type DubboException struct {
+	Type       string          // the java class, such as "java.lang.IllegalArgumentException"
+	Message    string          // detailMessage
+	StackTrace []string        // such as "com.foo.Bar.baz(Bar.java:10)"
+	Cause      *DubboException // the type of the cause is unknown
+	Exception  Any             // the decoded java exception
+}
+
+// the same as java.lang.Throwable.toString
+func (this *DubboException) Error() string {
+	if this.Type == "" {
+		return this.Message
+	}
+	if this.Message == "" {
+		return this.Type
+	}
+
+	return this.Type + ": " + this.Message
+}
+
+// newDubboException converts the java exception @v of class @typ.
+// The unregistered exception is decoded as map[Any]Any whose keys are the
+// fields of java.lang.Throwable.
+func newDubboException(typ string, v Any) *DubboException {
+	var e = &DubboException{Type: typ, Exception: v}
+
+	switch ex := v.(type) {
+	case POJO:
+		e.Type = ex.GetType()
+		if err, ok := v.(error); ok {
+			e.Message = err.Error()
+		}
+
+	case map[Any]Any:
+		e.Message, _ = ex["detailMessage"].(string)
+		if trace, ok := ex["stackTrace"].([]Any); ok {
+			for _, elem := range trace {
+				if m, ok := elem.(map[Any]Any); ok {
+					e.StackTrace = append(e.StackTrace, fmt.Sprintf("%v.%v(%v:%v)",
+						m["declaringClass"], m["methodName"], m["fileName"], m["lineNumber"]))
+				}
+			}
+		}
+		// the cause of a java exception without cause is itself
+		if cause, ok := ex["cause"].(map[Any]Any); ok && reflect.ValueOf(cause).Pointer() != reflect.ValueOf(ex).Pointer() {
+			e.Cause = newDubboException("", cause)
+		}
+
+	default:
+		e.Message = fmt.Sprintf("%v", v)
+	}
+
+	return e
+}
+
+//=====================================
+// header
+//=====================================
+
+func packDubboHeader(h DubboHeader, b []byte) []byte {
+	var flag = h.Serialization & SERIALIZATION_MASK
+	if h.Request {
+		flag |= FLAG_REQUEST
+	}
+	if h.TwoWay {
+		flag |= FLAG_TWOWAY
+	}
+	if h.Event {
+		flag |= FLAG_EVENT
+	}
+
+	b = append(b, DUBBO_MAGIC_HIGH, DUBBO_MAGIC_LOW, flag, h.Status)
+	b = append(b, PackInt64(h.ID)...)
+	return append(b, PackInt32(int32(h.BodyLen))...)
+}
+
+// DecodeDubboHeader parses the header at the beginning of @b.
+func DecodeDubboHeader(b []byte) (DubboHeader, error) {
+	var h DubboHeader
+
+	if len(b) < DUBBO_HEADER_LENGTH {
+		return h, ErrDubboHeaderNotEnough
+	}
+	if b[0] != DUBBO_MAGIC_HIGH || b[1] != DUBBO_MAGIC_LOW {
+		return h, ErrIllegalDubboMagic
+	}
+
+	h.Request = b[2]&FLAG_REQUEST != 0
+	h.TwoWay = b[2]&FLAG_TWOWAY != 0
+	h.Event = b[2]&FLAG_EVENT != 0
+	h.Serialization = b[2] & SERIALIZATION_MASK
+	h.Status = b[3]
+	h.ID = UnpackInt64(b[4:12])
+	h.BodyLen = int(binary.BigEndian.Uint32(b[12:16]))
+	if h.BodyLen > DUBBO_MAX_BODY_LENGTH {
+		return h, ErrDubboBodyTooLarge
+	}
+
+	return h, nil
+}
+
+//=====================================
+// encode
+//=====================================
+
+// EncodeDubboRequest packs @req as a dubbo packet whose body is encoded by hessian2.
+// body ::= dubbo-version path version method param-types arg* attachments
+func EncodeDubboRequest(req *DubboRequest) ([]byte, error) {
+	var (
+		err         error
+		e           = NewEncoder2()
+		attachments map[string]string
+	)
+
+	if req.Event {
+		e.encNull()
+	} else {
+		var (
+			dubboVersion = req.DubboVersion
+			version      = req.Version
+			paramTypes   = req.ParamTypes
+		)
+		if dubboVersion == "" {
+			dubboVersion = DUBBO_VERSION
+		}
+		if version == "" {
+			version = DUBBO_SERVICE_VERSION
+		}
+		if paramTypes == "" && len(req.Args) > 0 {
+			if paramTypes, err = GetParamTypes(req.Args...); err != nil {
+				return nil, err
+			}
+		}
+		e.encString(dubboVersion)
+		e.encString(req.Path)
+		e.encString(version)
+		e.encString(req.Method)
+		e.encString(paramTypes)
+		for i, arg := range req.Args {
+			if err = e.Encode(arg); err != nil {
+				return nil, fmt.Errorf("args[%d]: %s", i, err)
+			}
+		}
+
+		attachments = make(map[string]string, len(req.Attachments)+3)
+		for k, v := range req.Attachments {
+			attachments[k] = v
+		}
+		attachments[DUBBO_PATH_KEY] = req.Path
+		if _, ok := attachments[DUBBO_INTERFACE_KEY]; !ok {
+			attachments[DUBBO_INTERFACE_KEY] = req.Path
+		}
+		attachments[DUBBO_VERSION_KEY] = version
+		if err = e.Encode(attachments); err != nil {
+			return nil, err
+		}
+	}
+
+	return packDubbo(DubboHeader{
+		Request:       true,
+		TwoWay:        req.TwoWay,
+		Event:         req.Event,
+		Serialization: HESSIAN2_SERIALIZATION_ID,
+		ID:            req.ID,
+	}, e.Buffer())
+}
+
+// EncodeDubboResponse packs @rsp as a dubbo packet whose body is encoded by hessian2.
+// The body is "response-type [value | exception] [attachments]" if the status
+// is DUBBO_OK, otherwise it is the error message.
+func EncodeDubboResponse(rsp *DubboResponse) ([]byte, error) {
+	var (
+		err    error
+		e      = NewEncoder2()
+		status = rsp.Status
+	)
+
+	if status == 0 {
+		status = DUBBO_OK
+	}
+
+	switch {
+	case rsp.Event:
+		e.encNull()
+
+	case status != DUBBO_OK:
+		e.encString(rsp.ErrorMessage)
+
+	case rsp.Exception != nil:
+		e.encInt32(responseType(RESPONSE_WITH_EXCEPTION, rsp.Attachments))
+		var exception Any = javaThrowable{typ: JAVA_RUNTIME_EXCEPTION, message: rsp.Exception.Error()}
+		if de, ok := rsp.Exception.(*DubboException); ok {
+			exception = de.Exception
+			if exception == nil {
+				exception = javaThrowable{typ: de.Type, message: de.Message}
+			}
+		}
+		if err = e.Encode(exception); err != nil {
+			return nil, err
+		}
+
+	case rsp.Result == nil:
+		e.encInt32(responseType(RESPONSE_NULL_VALUE, rsp.Attachments))
+
+	default:
+		e.encInt32(responseType(RESPONSE_VALUE, rsp.Attachments))
+		if err = e.Encode(rsp.Result); err != nil {
+			return nil, err
+		}
+	}
+	if !rsp.Event && status == DUBBO_OK && len(rsp.Attachments) > 0 {
+		if err = e.Encode(rsp.Attachments); err != nil {
+			return nil, err
+		}
+	}
+
+	return packDubbo(DubboHeader{
+		Event:         rsp.Event,
+		Serialization: HESSIAN2_SERIALIZATION_ID,
+		Status:        status,
+		ID:            rsp.ID,
+	}, e.Buffer())
+}
+
+// responseType returns the response type with attachments if @attachments is not empty.
+func responseType(typ int32, attachments map[string]string) int32 {
+	if len(attachments) > 0 {
+		return typ + RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS
+	}
+
+	return typ
+}
+
+func packDubbo(h DubboHeader, body []byte) ([]byte, error) {
+	if len(body) > DUBBO_MAX_BODY_LENGTH {
+		return nil, ErrDubboBodyTooLarge
+	}
+
+	h.BodyLen = len(body)
+	var b = make([]byte, 0, DUBBO_HEADER_LENGTH+len(body))
+	b = packDubboHeader(h, b)
+	return append(b, body...), nil
+}
+
+//=====================================
+// decode
+//=====================================
+
+// DecodeDubboPacket parses the dubbo packet at the beginning of @b.
+// The return value is a *DubboRequest or a *DubboResponse and the length of
+// the packet. If @b is not a complete packet, the error is
+// ErrDubboHeaderNotEnough or ErrDubboBodyNotEnough.
+func DecodeDubboPacket(b []byte) (Any, int, error) {
+	return DecodeDubboPacketWithOptions(b, nil)
+}
+
+// DecodeDubboPacketWithOptions is the same as DecodeDubboPacket, except that
+// the body is decoded with options @opts.
+func DecodeDubboPacketWithOptions(b []byte, opts *DubboDecodeOptions) (Any, int, error) {
+	var (
+		err    error
+		header DubboHeader
+		pkg    Any
+	)
+
+	if header, err = DecodeDubboHeader(b); err != nil {
+		return nil, 0, err
+	}
+	if len(b) < DUBBO_HEADER_LENGTH+header.BodyLen {
+		return nil, 0, ErrDubboBodyNotEnough
+	}
+
+	pkg, err = decodeDubboBody(header, b[DUBBO_HEADER_LENGTH:DUBBO_HEADER_LENGTH+header.BodyLen], opts)
+	return pkg, DUBBO_HEADER_LENGTH + header.BodyLen, err
+}
+
+// ReadDubboPacket reads a dubbo packet from @r, such as a tcp connection.
+// The return value is a *DubboRequest or a *DubboResponse.
+func ReadDubboPacket(r io.Reader) (Any, error) {
+	return ReadDubboPacketWithOptions(r, nil)
+}
+
+// ReadDubboPacketWithOptions is the same as ReadDubboPacket, except that the
+// body is decoded with options @opts.
+func ReadDubboPacketWithOptions(r io.Reader, opts *DubboDecodeOptions) (Any, error) {
+	var (
+		err    error
+		b      [DUBBO_HEADER_LENGTH]byte
+		header DubboHeader
+		body   []byte
+	)
+
+	if _, err = io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	if header, err = DecodeDubboHeader(b[:]); err != nil {
+		return nil, err
+	}
+	body = make([]byte, header.BodyLen)
+	if _, err = io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	return decodeDubboBody(header, body, opts)
+}
+
+func decodeDubboBody(header DubboHeader, body []byte, opts *DubboDecodeOptions) (Any, error) {
+	if header.Serialization != HESSIAN2_SERIALIZATION_ID {
+		return nil, ErrDubboSerializationType
+	}
+
+	if header.Request {
+		return decodeDubboRequest(header, body, opts)
+	}
+	return decodeDubboResponse(header, body, opts)
+}
+
+func decodeDubboRequest(header DubboHeader, body []byte, opts *DubboDecodeOptions) (*DubboRequest, error) {
+	var (
+		err   error
+		n     int
+		types []string
+		arg   Any
+		v     Any
+		d     = opts.newDecoder(body)
+		req   = &DubboRequest{ID: header.ID, TwoWay: header.TwoWay, Event: header.Event}
+	)
+
+	if req.Event {
+		return req, nil
+	}
+
+	for _, s := range []*string{&req.DubboVersion, &req.Path, &req.Version, &req.Method, &req.ParamTypes} {
+		if *s, err = d.readString(); err != nil {
+			return nil, err
+		}
+	}
+
+	if types, err = splitDescriptor(req.ParamTypes); err != nil {
+		return nil, err
+	}
+	n = len(types)
+	for i := 0; i < n; i++ {
+		if arg, err = d.Decode(); err != nil {
+			return nil, fmt.Errorf("args[%d]: %s", i, err)
+		}
+		req.Args = append(req.Args, arg)
+	}
+
+	if v, err = d.Decode(); err != nil {
+		return nil, err
+	}
+	req.Attachments = toStringMap(v)
+
+	return req, nil
+}
+
+func decodeDubboResponse(header DubboHeader, body []byte, opts *DubboDecodeOptions) (*DubboResponse, error) {
+	var (
+		err   error
+		typ   int
+		class string
+		v     Any
+		d     = opts.newDecoder(body)
+		rsp   = &DubboResponse{ID: header.ID, Status: header.Status, Event: header.Event}
+	)
+
+	if rsp.Event {
+		return rsp, nil
+	}
+
+	if rsp.Status != DUBBO_OK {
+		if rsp.ErrorMessage, err = d.readString(); err != nil {
+			return nil, err
+		}
+		return rsp, nil
+	}
+
+	if typ, err = d.readInt(); err != nil {
+		return nil, err
+	}
+	switch int32(typ) {
+	case RESPONSE_NULL_VALUE, RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
+	case RESPONSE_VALUE, RESPONSE_VALUE_WITH_ATTACHMENTS:
+		if rsp.Result, err = d.Decode(); err != nil {
+			return nil, err
+		}
+	case RESPONSE_WITH_EXCEPTION, RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS:
+		if class, v, err = d.decodeObject(); err != nil {
+			return nil, err
+		}
+		rsp.Exception = newDubboException(class, v)
+	default:
+		return nil, fmt.Errorf("illegal dubbo response type %d", typ)
+	}
+
+	if int32(typ) >= RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS {
+		if v, err = d.Decode(); err != nil {
+			return nil, err
+		}
+		rsp.Attachments = toStringMap(v)
+	}
+
+	return rsp, nil
+}
+
+// splitDescriptor splits the jvm type descriptor of the parameters.
+// "Ljava/lang/String;I[J" -> ["Ljava/lang/String;", "I", "[J"]
+func splitDescriptor(desc string) ([]string, error) {
+	var (
+		types []string
+		begin int
+	)
+
+	for i := 0; i < len(desc); i++ {
+		switch desc[i] {
+		case '[':
+			continue
+		case 'V', 'Z', 'B', 'C', 'D', 'F', 'I', 'J', 'S':
+		case 'L':
+			for i < len(desc) && desc[i] != ';' {
+				i++
+			}
+			if i == len(desc) {
+				return nil, fmt.Errorf("illegal type descriptor %q", desc)
+			}
+		default:
+			return nil, fmt.Errorf("illegal type descriptor %q", desc)
+		}
+		types = append(types, desc[begin:i+1])
+		begin = i + 1
+	}
+	if begin != len(desc) {
+		return nil, fmt.Errorf("illegal type descriptor %q", desc)
+	}
+
+	return types, nil
+}
+
+// toStringMap converts the decoded attachments to map[string]string.
+func toStringMap(v Any) map[string]string {
+	var m, ok = v.(map[Any]Any)
+	if !ok {
+		return nil
+	}
+
+	var attachments = make(map[string]string, len(m))
+	for k, v := range m {
+		key, ok := k.(string)
+		if !ok {
+			continue
+		}
+		if v == nil {
+			attachments[key] = ""
+			continue
+		}
+		attachments[key] = fmt.Sprintf("%v", v)
+	}
+
+	return attachments
+}
@@ -0,0 +1,446 @@
+/******************************************************
+# DESC    : dubbo tcp client
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 17:05
+# FILE    : dubbo_client.go
+******************************************************/
+
+package hessian
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DUBBO_DEFAULT_TIMEOUT         = 3 * time.Second
+	DUBBO_DEFAULT_HEARTBEAT       = 60 * time.Second
+	DUBBO_DEFAULT_CONNECT_TIMEOUT = 3 * time.Second
+)
+
+var (
+	ErrDubboClientClosed = fmt.Errorf("dubbo client is closed")
+	ErrDubboConnClosed   = fmt.Errorf("dubbo connection is closed")
+	ErrDubboTimeout      = fmt.Errorf("dubbo request timeout")
+)
+
+// DubboError is the error of the response whose status is not DUBBO_OK.
+type DubboError struct {
+	Status  byte
+	Message string
+}
+
+func (this *DubboError) Error() string {
+	return fmt.Sprintf("dubbo status %d: %s", this.Status, this.Message)
+}
+
+// DubboClient calls the dubbo services on the provider @addr by a long-lived
+// tcp connection. The concurrent calls share the connection and the responses
+// are dispatched by the request id. The connection is kept alive by heartbeat,
+// and it is reconnected when it is broken.
+type DubboClient struct {
+	addr       string
+	timeout    time.Duration
+	heartbeat  time.Duration
+	maxBodyLen int
+	opts       DubboDecodeOptions
+
+	id   int64 // the last request id, atomic
+	lock sync.Mutex
+	conn *dubboConn
+	once sync.Once     // starts keepalive
+	done chan struct{} // closed by Close
+}
+
+// the connection to the provider
+type dubboConn struct {
+	lastRead int64 // unix nano, atomic
+	net.Conn
+	wlock      sync.Mutex // serializes the writes
+	lock       sync.Mutex // protects pending & err
+	pending    map[int64]chan *DubboResponse
+	err        error // not nil if the connection is closed
+	maxBodyLen int
+	opts       DubboDecodeOptions
+}
+
+func NewDubboClient(addr string) *DubboClient {
+	return &DubboClient{
+		addr:       addr,
+		timeout:    DUBBO_DEFAULT_TIMEOUT,
+		heartbeat:  DUBBO_DEFAULT_HEARTBEAT,
+		maxBodyLen: DUBBO_MAX_BODY_LENGTH,
+		done:       make(chan struct{}),
+	}
+}
+
+func (this *DubboClient) Addr() string {
+	return this.addr
+}
+
+// SetTimeout sets the timeout of a request, 3s by default.
+func (this *DubboClient) SetTimeout(timeout time.Duration) {
+	this.lock.Lock()
+	this.timeout = timeout
+	this.lock.Unlock()
+}
+
+// SetHeartbeat sets the heartbeat period, 60s by default. The connection is
+// closed if nothing is received from the provider in 3 periods. No heartbeat
+// is sent if @heartbeat is not positive. It should be called before the first request.
+func (this *DubboClient) SetHeartbeat(heartbeat time.Duration) {
+	this.lock.Lock()
+	this.heartbeat = heartbeat
+	this.lock.Unlock()
+}
+
+// SetMaxBodyLength sets the max body length of the packets from the provider,
+// DUBBO_MAX_BODY_LENGTH by default. The connection is closed if a packet is
+// longer. It should be called before the first request.
+func (this *DubboClient) SetMaxBodyLength(n int) {
+	this.lock.Lock()
+	if 0 < n && n <= DUBBO_MAX_BODY_LENGTH {
+		this.maxBodyLen = n
+	}
+	this.lock.Unlock()
+}
+
+// SetPOJORegistry makes the client decode the responses by registry @r
+// instead of the global registry of RegisterPOJO. It should be called before
+// the first request.
+func (this *DubboClient) SetPOJORegistry(r *POJORegistry) {
+	this.lock.Lock()
+	this.opts.POJORegistry = r
+	this.lock.Unlock()
+}
+
+// SetUnknownTypePolicy sets the way to decode the objects of unknown java
+// class in the responses, see Decoder.SetUnknownTypePolicy. It should be
+// called before the first request.
+func (this *DubboClient) SetUnknownTypePolicy(policy UnknownTypePolicy) {
+	this.lock.Lock()
+	this.opts.UnknownTypePolicy = policy
+	this.lock.Unlock()
+}
+
+// SetUnknownTypeHandler sets the handler of the objects of unknown java class
+// in the responses, see Decoder.SetUnknownTypeHandler. It should be called
+// before the first request.
+func (this *DubboClient) SetUnknownTypeHandler(handler UnknownTypeHandler) {
+	this.lock.Lock()
+	this.opts.UnknownTypeHandler = handler
+	this.lock.Unlock()
+}
+
+// Invoke calls @method of the service @path and returns the result.
+// The exception thrown by the provider is returned as *DubboException,
+// and the response whose status is not DUBBO_OK is returned as *DubboError.
+func (this *DubboClient) Invoke(path string, method string, args ...Any) (Any, error) {
+	var rsp, err = this.Call(&DubboRequest{
+		TwoWay: true,
+		Path:   path,
+		Method: method,
+		Args:   args,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Exception != nil {
+		return nil, rsp.Exception
+	}
+
+	return rsp.Result, nil
+}
+
+// Call sends @req and waits for its response if @req.TwoWay is true.
+// A copy of @req is sent, whose request id is allocated by Call and whose
+// attachments have the timeout, so @req is not modified.
+func (this *DubboClient) Call(r *DubboRequest) (*DubboResponse, error) {
+	var (
+		err     error
+		b       []byte
+		conn    *dubboConn
+		ch      chan *DubboResponse
+		rsp     *DubboResponse
+		timeout = this.getTimeout()
+		req     = *r
+	)
+
+	req.ID = atomic.AddInt64(&this.id, 1)
+	if !req.Event {
+		req.Attachments = make(map[string]string, len(r.Attachments)+1)
+		for k, v := range r.Attachments {
+			req.Attachments[k] = v
+		}
+		if _, ok := req.Attachments[DUBBO_TIMEOUT_KEY]; !ok {
+			req.Attachments[DUBBO_TIMEOUT_KEY] = strconv.FormatInt(int64(timeout/time.Millisecond), 10)
+		}
+	}
+	if b, err = EncodeDubboRequest(&req); err != nil {
+		return nil, err
+	}
+
+	if conn, err = this.getConn(); err != nil {
+		return nil, err
+	}
+	if req.TwoWay {
+		if ch, err = conn.addPending(req.ID); err != nil {
+			return nil, err
+		}
+		defer conn.removePending(req.ID)
+	}
+	if err = conn.write(b); err != nil {
+		return nil, err
+	}
+	if !req.TwoWay {
+		return nil, nil
+	}
+
+	var timer = time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case rsp = <-ch:
+		if rsp == nil {
+			return nil, conn.getErr()
+		}
+	case <-timer.C:
+		return nil, ErrDubboTimeout
+	}
+
+	if !rsp.Event && rsp.Status != DUBBO_OK {
+		return nil, &DubboError{Status: rsp.Status, Message: rsp.ErrorMessage}
+	}
+
+	return rsp, nil
+}
+
+// Close closes the connection, and the pending calls fail with ErrDubboClientClosed.
+func (this *DubboClient) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	select {
+	case <-this.done:
+		return nil
+	default:
+	}
+	close(this.done)
+	if this.conn != nil {
+		this.conn.close(ErrDubboClientClosed)
+		this.conn = nil
+	}
+
+	return nil
+}
+
+func (this *DubboClient) getTimeout() time.Duration {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.timeout
+}
+
+// getConn returns the connection, and connects to the provider if the
+// connection is not established or it has been broken.
+func (this *DubboClient) getConn() (*dubboConn, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	select {
+	case <-this.done:
+		return nil, ErrDubboClientClosed
+	default:
+	}
+	if this.conn != nil && this.conn.getErr() == nil {
+		return this.conn, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", this.addr, DUBBO_DEFAULT_CONNECT_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	this.conn = &dubboConn{
+		Conn:       conn,
+		pending:    make(map[int64]chan *DubboResponse),
+		lastRead:   time.Now().UnixNano(),
+		maxBodyLen: this.maxBodyLen,
+		opts:       this.opts,
+	}
+	go this.conn.loop()
+	if this.heartbeat > 0 {
+		this.once.Do(func() { go this.keepalive(this.heartbeat) })
+	}
+
+	return this.conn, nil
+}
+
+// keepalive sends heartbeat periodically, closes the connection if the
+// provider has not responded for a long time, and reconnects the broken connection.
+func (this *DubboClient) keepalive(heartbeat time.Duration) {
+	var ticker = time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-this.done:
+			return
+		case <-ticker.C:
+		}
+
+		this.lock.Lock()
+		var conn = this.conn
+		this.lock.Unlock()
+		if conn == nil {
+			continue
+		}
+		if conn.getErr() != nil {
+			this.getConn()
+			continue
+		}
+		if time.Since(time.Unix(0, atomic.LoadInt64(&conn.lastRead))) > 3*heartbeat {
+			conn.close(fmt.Errorf("dubbo heartbeat timeout: %s", this.addr))
+			continue
+		}
+		b, _ := EncodeDubboRequest(&DubboRequest{ID: atomic.AddInt64(&this.id, 1), TwoWay: true, Event: true})
+		conn.write(b)
+	}
+}
+
+//=====================================
+// dubboConn
+//=====================================
+
+func (this *dubboConn) getErr() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.err
+}
+
+func (this *dubboConn) addPending(id int64) (chan *DubboResponse, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.err != nil {
+		return nil, this.err
+	}
+	var ch = make(chan *DubboResponse, 1)
+	this.pending[id] = ch
+
+	return ch, nil
+}
+
+func (this *dubboConn) removePending(id int64) chan *DubboResponse {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	var ch = this.pending[id]
+	delete(this.pending, id)
+
+	return ch
+}
+
+func (this *dubboConn) write(b []byte) error {
+	this.wlock.Lock()
+	defer this.wlock.Unlock()
+
+	if _, err := this.Write(b); err != nil {
+		this.close(err)
+		return err
+	}
+
+	return nil
+}
+
+// close closes the connection and wakes up all the pending calls.
+func (this *dubboConn) close(err error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.err != nil {
+		return
+	}
+	this.err = err
+	this.Conn.Close()
+	for id, ch := range this.pending {
+		close(ch)
+		delete(this.pending, id)
+	}
+}
+
+// loop reads the packets from the provider until the connection is broken.
+func (this *dubboConn) loop() {
+	var (
+		err    error
+		broken bool
+		header DubboHeader
+		pkg    Any
+		buf    [DUBBO_HEADER_LENGTH]byte
+		body   []byte
+		r      = bufio.NewReader(this.Conn)
+	)
+
+	for {
+		if _, err = io.ReadFull(r, buf[:]); err == nil {
+			header, err = DecodeDubboHeader(buf[:])
+		}
+		if err == nil && (header.BodyLen < 0 || header.BodyLen > this.maxBodyLen) {
+			err = ErrDubboBodyTooLarge
+		}
+		if err == nil {
+			body = make([]byte, header.BodyLen)
+			_, err = io.ReadFull(r, body)
+		}
+		if err != nil {
+			this.close(fmt.Errorf("%s: %s", ErrDubboConnClosed, err))
+			return
+		}
+		atomic.StoreInt64(&this.lastRead, time.Now().UnixNano())
+
+		// the packet is skipped if its body is illegal, and the caller is
+		// responded with DUBBO_CLIENT_ERROR. The connection is closed if the
+		// decoder panics, for the later packets can not be trusted.
+		if pkg, broken, err = this.decode(header, body); err != nil {
+			if !header.Request {
+				if ch := this.removePending(header.ID); ch != nil {
+					ch <- &DubboResponse{ID: header.ID, Status: DUBBO_CLIENT_ERROR, ErrorMessage: err.Error()}
+				}
+			}
+			if broken {
+				this.close(fmt.Errorf("%s: %s", ErrDubboConnClosed, err))
+				return
+			}
+			continue
+		}
+
+		switch p := pkg.(type) {
+		case *DubboRequest:
+			// answer the heartbeat of the provider
+			if p.Event && p.TwoWay {
+				b, _ := EncodeDubboResponse(&DubboResponse{ID: p.ID, Event: true})
+				this.write(b)
+			}
+		case *DubboResponse:
+			if ch := this.removePending(p.ID); ch != nil {
+				ch <- p
+			}
+		}
+	}
+}
+
+// decode decodes the body of a packet. The panic of the decoder is returned
+// as an error, and @broken is true then.
+func (this *dubboConn) decode(header DubboHeader, body []byte) (pkg Any, broken bool, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			pkg, broken, err = nil, true, fmt.Errorf("illegal dubbo packet %d: %v", header.ID, e)
+		}
+	}()
+
+	pkg, err = decodeDubboBody(header, body, &this.opts)
+	return pkg, false, err
+}
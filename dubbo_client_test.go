@@ -0,0 +1,373 @@
+/******************************************************
+# DESC    : dubbo_client.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 17:05
+# FILE    : dubbo_client_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// go test -v -run TestDubboClient
+
+type Sleeper struct{}
+
+func (s *Sleeper) Sleep(ms int32) int32 {
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return ms
+}
+
+// Bomb returns an object whose deserializer panics
+type Bomb struct{}
+
+func (b *Bomb) Explode() Any {
+	return &TypedMap{Type: "test.Bomb", Map: map[string]int32{"a": 1}}
+}
+
+// Unhashable returns a map whose key is decoded as a list, which is not a
+// legal go map key
+func (b *Bomb) Unhashable() map[*[]int32]int32 {
+	return map[*[]int32]int32{&[]int32{1}: 1}
+}
+
+type bomb struct{}
+
+// Garage returns a test.Van, which is registered in no global registry
+type Garage struct{}
+
+func (g *Garage) Get(name string) Van {
+	return Van{Name: name}
+}
+
+type bombSerializer struct{}
+
+func (bombSerializer) Marshal(v Any) (Any, error)   { return nil, nil }
+func (bombSerializer) Unmarshal(v Any) (Any, error) { panic("bomb") }
+
+func init() {
+	RegisterSerializer(bomb{}, "test.Bomb", bombSerializer{})
+}
+
+// fakeProvider is a dubbo provider which serves the services of Server.
+type fakeProvider struct {
+	net.Listener
+	server *Server
+
+	accepts    int32 // atomic
+	heartbeats int32 // the heartbeats received, atomic
+	pongs      int32 // the heartbeat responses received, atomic
+	mute       int32 // do not answer the heartbeat if it is 1, atomic
+
+	lock  sync.Mutex
+	conns []net.Conn
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() = error:%v", err)
+	}
+
+	var p = &fakeProvider{Listener: l, server: NewServer()}
+	p.server.Register("com.foo.Math", &Math{})
+	p.server.Register("com.foo.Sleeper", &Sleeper{})
+	p.server.Register("com.foo.Bomb", &Bomb{})
+	p.server.Register("com.foo.Garage", &Garage{})
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&p.accepts, 1)
+			p.lock.Lock()
+			p.conns = append(p.conns, conn)
+			p.lock.Unlock()
+			go p.serve(conn)
+		}
+	}()
+
+	return p
+}
+
+func (p *fakeProvider) serve(conn net.Conn) {
+	var (
+		lock  sync.Mutex
+		write = func(rsp *DubboResponse) {
+			b, _ := EncodeDubboResponse(rsp)
+			lock.Lock()
+			conn.Write(b)
+			lock.Unlock()
+		}
+		r = bufio.NewReader(conn)
+	)
+
+	// ping the consumer
+	b, _ := EncodeDubboRequest(&DubboRequest{ID: 1, TwoWay: true, Event: true})
+	conn.Write(b)
+
+	for {
+		pkg, err := ReadDubboPacket(r)
+		if err != nil {
+			conn.Close()
+			return
+		}
+
+		if rsp, ok := pkg.(*DubboResponse); ok {
+			if rsp.Event {
+				atomic.AddInt32(&p.pongs, 1)
+			}
+			continue
+		}
+		req := pkg.(*DubboRequest)
+		if req.Event {
+			atomic.AddInt32(&p.heartbeats, 1)
+			if atomic.LoadInt32(&p.mute) == 0 {
+				write(&DubboResponse{ID: req.ID, Event: true})
+			}
+			continue
+		}
+
+		// the requests are served concurrently
+		go func() {
+			res, err := p.server.Invoke(req.Path, req.Method, req.Args)
+			var rsp = &DubboResponse{ID: req.ID, Result: res, Exception: err}
+			if err == ErrNoSuchService {
+				rsp = &DubboResponse{ID: req.ID, Status: DUBBO_SERVICE_NOT_FOUND, ErrorMessage: err.Error()}
+			}
+			write(rsp)
+		}()
+	}
+}
+
+func (p *fakeProvider) closeConns() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+func TestDubboClient(t *testing.T) {
+	var (
+		err error
+		res Any
+		p   = newFakeProvider(t)
+		c   = NewDubboClient(p.Addr().String())
+	)
+	defer p.Close()
+	defer c.Close()
+
+	if res, err = c.Invoke("com.foo.Math", "Add", int32(100), int32(200)); err != nil || res != int32(300) {
+		t.Errorf("Add(100, 200) = res:%v, err:%v", res, err)
+	}
+	if _, err = c.Invoke("com.foo.Math", "Div", int32(1), int32(0)); err == nil {
+		t.Errorf("Div(1, 0) should fail")
+	} else if e, ok := err.(*DubboException); !ok || e.Message != "divide by zero" {
+		t.Errorf("Div(1, 0) = err:%#v", err)
+	}
+	if _, err = c.Invoke("com.foo.Echo", "Echo", "hello"); err == nil {
+		t.Errorf("Echo(hello) should fail")
+	} else if e, ok := err.(*DubboError); !ok || e.Status != DUBBO_SERVICE_NOT_FOUND {
+		t.Errorf("Echo(hello) = err:%#v", err)
+	}
+
+	// the later request is responded earlier
+	var wg sync.WaitGroup
+	for i := 10; i > 0; i-- {
+		wg.Add(1)
+		go func(ms int32) {
+			defer wg.Done()
+			if res, err := c.Invoke("com.foo.Sleeper", "Sleep", ms); err != nil || res != ms {
+				t.Errorf("Sleep(%d) = res:%v, err:%v", ms, res, err)
+			}
+		}(int32(i * 10))
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&p.accepts); n != 1 {
+		t.Errorf("the count of connections = %d", n)
+	}
+
+	c.SetTimeout(20 * time.Millisecond)
+	if _, err = c.Invoke("com.foo.Sleeper", "Sleep", int32(200)); err != ErrDubboTimeout {
+		t.Errorf("Sleep(200) = err:%v", err)
+	}
+	c.SetTimeout(DUBBO_DEFAULT_TIMEOUT)
+
+	// the client answers the heartbeat of the provider
+	if n := atomic.LoadInt32(&p.pongs); n != 1 {
+		t.Errorf("the count of heartbeat responses = %d", n)
+	}
+
+	c.Close()
+	if _, err = c.Invoke("com.foo.Math", "Add", int32(1), int32(2)); err != ErrDubboClientClosed {
+		t.Errorf("Add(1, 2) after Close() = err:%v", err)
+	}
+}
+
+func TestDubboClientReconnect(t *testing.T) {
+	var (
+		err error
+		res Any
+		p   = newFakeProvider(t)
+		c   = NewDubboClient(p.Addr().String())
+	)
+	defer p.Close()
+	defer c.Close()
+
+	c.SetHeartbeat(10 * time.Millisecond)
+	if res, err = c.Invoke("com.foo.Math", "Add", int32(1), int32(2)); err != nil || res != int32(3) {
+		t.Fatalf("Add(1, 2) = res:%v, err:%v", res, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&p.heartbeats); n < 2 {
+		t.Errorf("the count of heartbeats = %d", n)
+	}
+
+	// the connection is broken, and it is reconnected by the next request
+	p.closeConns()
+	time.Sleep(5 * time.Millisecond)
+	if res, err = c.Invoke("com.foo.Math", "Add", int32(1), int32(2)); err != nil || res != int32(3) {
+		t.Errorf("Add(1, 2) after reconnection = res:%v, err:%v", res, err)
+	}
+	if n := atomic.LoadInt32(&p.accepts); n != 2 {
+		t.Errorf("the count of connections = %d", n)
+	}
+
+	// the provider does not respond to heartbeat, the connection is closed and reconnected
+	atomic.StoreInt32(&p.mute, 1)
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&p.accepts); n < 3 {
+		t.Errorf("the count of connections = %d", n)
+	}
+}
+
+// go test -v -run TestDubboClientIllegalPacket
+
+func TestDubboClientIllegalPacket(t *testing.T) {
+	var (
+		err error
+		res Any
+		rsp *DubboResponse
+		p   = newFakeProvider(t)
+		c   = NewDubboClient(p.Addr().String())
+	)
+	defer p.Close()
+	defer c.Close()
+
+	// the call fails and the connection is closed if the decoder panics
+	if _, err = c.Invoke("com.foo.Bomb", "Explode"); err == nil {
+		t.Errorf("Explode() should fail")
+	} else if e, ok := err.(*DubboError); !ok || e.Status != DUBBO_CLIENT_ERROR {
+		t.Errorf("Explode() = err:%#v", err)
+	}
+	if res, err = c.Invoke("com.foo.Math", "Add", int32(1), int32(2)); err != nil || res != int32(3) {
+		t.Errorf("Add(1, 2) after the panic = res:%v, err:%v", res, err)
+	}
+	if n := atomic.LoadInt32(&p.accepts); n != 2 {
+		t.Errorf("the count of connections = %d", n)
+	}
+
+	// the illegal response fails its own call only, not the in-flight ones
+	var done = make(chan error, 1)
+	go func() {
+		res, err := c.Invoke("com.foo.Sleeper", "Sleep", int32(50))
+		if err == nil && res != int32(50) {
+			err = fmt.Errorf("Sleep(50) = res:%v", res)
+		}
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if _, err = c.Invoke("com.foo.Bomb", "Unhashable"); err == nil {
+		t.Errorf("Unhashable() should fail")
+	} else if e, ok := err.(*DubboError); !ok || e.Status != DUBBO_CLIENT_ERROR {
+		t.Errorf("Unhashable() = err:%#v", err)
+	}
+	if err = <-done; err != nil {
+		t.Errorf("the in-flight call after the illegal response = err:%v", err)
+	}
+	if res, err = c.Invoke("com.foo.Math", "Add", int32(1), int32(2)); err != nil || res != int32(3) {
+		t.Errorf("Add(1, 2) after the illegal response = res:%v, err:%v", res, err)
+	}
+	if n := atomic.LoadInt32(&p.accepts); n != 2 {
+		t.Errorf("the count of connections after the illegal response = %d", n)
+	}
+
+	// the request of the caller is not modified
+	var req = &DubboRequest{
+		TwoWay:      true,
+		Path:        "com.foo.Math",
+		Method:      "Add",
+		Args:        []Any{int32(1), int32(2)},
+		Attachments: map[string]string{"k": "v"},
+	}
+	if rsp, err = c.Call(req); err != nil || rsp.Result != int32(3) {
+		t.Errorf("Call(Add) = rsp:%#v, err:%v", rsp, err)
+	}
+	if req.ID != 0 || len(req.Attachments) != 1 {
+		t.Errorf("Call() modifies the request: %#v", req)
+	}
+
+	// the packet longer than the max body length closes the connection
+	var c2 = NewDubboClient(p.Addr().String())
+	defer c2.Close()
+	c2.SetMaxBodyLength(1)
+	if res, err = c2.Invoke("com.foo.Math", "Add", int32(1), int32(2)); err == nil {
+		t.Errorf("Add(1, 2) with max body length 1 = res:%v", res)
+	}
+}
+
+// go test -v -run TestDubboClientPOJORegistry
+
+func TestDubboClientPOJORegistry(t *testing.T) {
+	var (
+		err error
+		res Any
+		r   = NewPOJORegistry()
+		p   = newFakeProvider(t)
+		c   = NewDubboClient(p.Addr().String())
+		c2  = NewDubboClient(p.Addr().String())
+	)
+	defer p.Close()
+	defer c.Close()
+	defer c2.Close()
+
+	r.RegisterPOJO(Van{})
+	c.SetPOJORegistry(r)
+	if res, err = c.Invoke("com.foo.Garage", "Get", "a"); err != nil || !reflect.DeepEqual(res, &Van{Name: "a"}) {
+		t.Errorf("Get(a) = res:%#v, err:%v", res, err)
+	}
+	// the other client decodes test.Van by the global registry
+	if res, err = c2.Invoke("com.foo.Garage", "Get", "a"); err != nil || !reflect.DeepEqual(res, map[Any]Any{"name": "a"}) {
+		t.Errorf("Get(a) by the global registry = res:%#v, err:%v", res, err)
+	}
+
+	// test.Van is of unknown java class without the registry
+	var (
+		c3 = NewDubboClient(p.Addr().String())
+		c4 = NewDubboClient(p.Addr().String())
+	)
+	defer c3.Close()
+	defer c4.Close()
+	c3.SetUnknownTypeHandler(func(o *Object) (Any, error) { return o.Type, nil })
+	if res, err = c3.Invoke("com.foo.Garage", "Get", "a"); err != nil || res != "test.Van" {
+		t.Errorf("Get(a) by the unknown type handler = res:%#v, err:%v", res, err)
+	}
+	c4.SetUnknownTypePolicy(UNKNOWN_TYPE_ERROR)
+	if res, err = c4.Invoke("com.foo.Garage", "Get", "a"); err == nil {
+		t.Errorf("Get(a) by UNKNOWN_TYPE_ERROR = res:%#v", res)
+	}
+}
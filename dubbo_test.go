@@ -0,0 +1,258 @@
+/******************************************************
+# DESC    : dubbo.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 15:10
+# FILE    : dubbo_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// go test -v -run TestDubbo
+
+func TestDubboRequest(t *testing.T) {
+	var (
+		err error
+		b   []byte
+		pkg Any
+		n   int
+		req = &DubboRequest{
+			ID:          1,
+			TwoWay:      true,
+			Path:        "com.foo.UserService",
+			Method:      "GetUser",
+			ParamTypes:  "Ljava/lang/String;I[J",
+			Args:        []Any{"alex", int32(1), []Any{int64(1), int64(2)}},
+			Attachments: map[string]string{DUBBO_TIMEOUT_KEY: "3000"},
+		}
+	)
+
+	if b, err = EncodeDubboRequest(req); err != nil {
+		t.Fatalf("EncodeDubboRequest() = error:%v", err)
+	}
+	if b[0] != 0xda || b[1] != 0xbb || b[2] != FLAG_REQUEST|FLAG_TWOWAY|HESSIAN2_SERIALIZATION_ID {
+		t.Errorf("dubbo header = %s", SprintHex(b[:DUBBO_HEADER_LENGTH]))
+	}
+	if UnpackInt64(b[4:12]) != 1 || int(UnpackInt32(b[12:16])) != len(b)-DUBBO_HEADER_LENGTH {
+		t.Errorf("dubbo header = %s", SprintHex(b[:DUBBO_HEADER_LENGTH]))
+	}
+
+	// the body starts with dubbo version, service path, service version and method name
+	var e = NewEncoder2()
+	for _, s := range []string{DUBBO_VERSION, req.Path, DUBBO_SERVICE_VERSION, req.Method, req.ParamTypes} {
+		e.Encode(s)
+	}
+	if !bytes.HasPrefix(b[DUBBO_HEADER_LENGTH:], e.Buffer()) {
+		t.Errorf("dubbo body = %s", SprintHex(b[DUBBO_HEADER_LENGTH:]))
+	}
+
+	for i := 0; i < len(b); i++ {
+		if _, _, err = DecodeDubboPacket(b[:i]); err != ErrDubboHeaderNotEnough && err != ErrDubboBodyNotEnough {
+			t.Fatalf("DecodeDubboPacket(%d bytes) = error:%v", i, err)
+		}
+	}
+	if pkg, n, err = DecodeDubboPacket(append(b, 0xda)); err != nil || n != len(b) {
+		t.Fatalf("DecodeDubboPacket() = length:%d, error:%v", n, err)
+	}
+	got := pkg.(*DubboRequest)
+	want := *req
+	want.DubboVersion = DUBBO_VERSION
+	want.Version = DUBBO_SERVICE_VERSION
+	want.Attachments = map[string]string{
+		DUBBO_TIMEOUT_KEY:   "3000",
+		DUBBO_PATH_KEY:      req.Path,
+		DUBBO_INTERFACE_KEY: req.Path,
+		DUBBO_VERSION_KEY:   DUBBO_SERVICE_VERSION,
+	}
+	if !reflect.DeepEqual(got, &want) {
+		t.Errorf("DecodeDubboPacket() = %#v, want %#v", got, &want)
+	}
+
+	// heartbeat
+	if b, err = EncodeDubboRequest(&DubboRequest{ID: 2, TwoWay: true, Event: true}); err != nil {
+		t.Fatalf("EncodeDubboRequest(heartbeat) = error:%v", err)
+	}
+	if b[2] != FLAG_REQUEST|FLAG_TWOWAY|FLAG_EVENT|HESSIAN2_SERIALIZATION_ID || !bytes.Equal(b[DUBBO_HEADER_LENGTH:], []byte{'N'}) {
+		t.Errorf("EncodeDubboRequest(heartbeat) = %s", SprintHex(b))
+	}
+	if pkg, err = ReadDubboPacket(bytes.NewReader(b)); err != nil || !pkg.(*DubboRequest).Event {
+		t.Errorf("ReadDubboPacket(heartbeat) = %#v, error:%v", pkg, err)
+	}
+}
+
+func TestDubboResponse(t *testing.T) {
+	var cases = []struct {
+		rsp  *DubboResponse
+		body []byte
+	}{
+		{&DubboResponse{ID: 1, Result: "hello"}, []byte{0x91, 0x05, 'h', 'e', 'l', 'l', 'o'}},
+		{&DubboResponse{ID: 2, Status: DUBBO_OK}, []byte{0x92}},
+		{&DubboResponse{ID: 3, Status: DUBBO_SERVICE_NOT_FOUND, ErrorMessage: "no"}, []byte{0x02, 'n', 'o'}},
+		{&DubboResponse{ID: 4, Event: true}, []byte{'N'}},
+	}
+
+	for _, c := range cases {
+		b, err := EncodeDubboResponse(c.rsp)
+		if err != nil {
+			t.Fatalf("EncodeDubboResponse(%d) = error:%v", c.rsp.ID, err)
+		}
+		if !bytes.Equal(b[DUBBO_HEADER_LENGTH:], c.body) {
+			t.Errorf("EncodeDubboResponse(%d) = %s, want %s", c.rsp.ID, SprintHex(b[DUBBO_HEADER_LENGTH:]), SprintHex(c.body))
+		}
+
+		pkg, err := ReadDubboPacket(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("ReadDubboPacket(%d) = error:%v", c.rsp.ID, err)
+		}
+		rsp := pkg.(*DubboResponse)
+		if c.rsp.Status == 0 {
+			c.rsp.Status = DUBBO_OK
+		}
+		if !reflect.DeepEqual(rsp, c.rsp) {
+			t.Errorf("ReadDubboPacket(%d) = %#v, want %#v", c.rsp.ID, rsp, c.rsp)
+		}
+	}
+
+	b, _ := EncodeDubboResponse(&DubboResponse{ID: 5, Exception: fmt.Errorf("divide by zero")})
+	if !bytes.Contains(b, []byte(JAVA_RUNTIME_EXCEPTION)) {
+		t.Errorf("EncodeDubboResponse(exception) = %s", SprintHex(b))
+	}
+	pkg, _, err := DecodeDubboPacket(b)
+	if err != nil {
+		t.Fatalf("DecodeDubboPacket(exception) = error:%v", err)
+	}
+	if rsp := pkg.(*DubboResponse); rsp.Exception == nil || rsp.Exception.Error() != JAVA_RUNTIME_EXCEPTION+": divide by zero" {
+		t.Errorf("DecodeDubboPacket(exception) = %#v", rsp)
+	}
+
+	b[0] = 0
+	if _, _, err = DecodeDubboPacket(b); err != ErrIllegalDubboMagic {
+		t.Errorf("DecodeDubboPacket(illegal magic) = error:%v", err)
+	}
+}
+
+func TestDubboResponseAttachments(t *testing.T) {
+	var (
+		attachments = map[string]string{"traceId": "1"}
+		cases       = []struct {
+			rsp *DubboResponse
+			typ byte
+		}{
+			{&DubboResponse{Result: "hello", Attachments: attachments}, 0x94},
+			{&DubboResponse{Attachments: attachments}, 0x95},
+			{&DubboResponse{Exception: &DubboException{Type: "java.io.IOException", Message: "eof"}, Attachments: attachments}, 0x93},
+		}
+	)
+
+	for _, c := range cases {
+		b, err := EncodeDubboResponse(c.rsp)
+		if err != nil {
+			t.Fatalf("EncodeDubboResponse() = error:%v", err)
+		}
+		if b[DUBBO_HEADER_LENGTH] != c.typ {
+			t.Errorf("EncodeDubboResponse() = %s", SprintHex(b))
+		}
+		pkg, _, err := DecodeDubboPacket(b)
+		if err != nil {
+			t.Fatalf("DecodeDubboPacket() = error:%v", err)
+		}
+		rsp := pkg.(*DubboResponse)
+		if rsp.Result != c.rsp.Result || !reflect.DeepEqual(rsp.Attachments, attachments) {
+			t.Errorf("DecodeDubboPacket() = %#v", rsp)
+		}
+		if c.rsp.Exception != nil && (rsp.Exception == nil || rsp.Exception.Error() != "java.io.IOException: eof") {
+			t.Errorf("DecodeDubboPacket() = exception:%v", rsp.Exception)
+		}
+	}
+}
+
+// the exception thrown by java provider
+func TestDubboResponseException(t *testing.T) {
+	var e = NewEncoder2()
+
+	e.encInt32(RESPONSE_WITH_EXCEPTION)
+	e.buffer = append(e.buffer, BC_OBJECT_DEF)
+	e.encString("java.lang.IllegalStateException")
+	e.encInt32(4)
+	for _, field := range []string{"detailMessage", "cause", "stackTrace", "suppressedExceptions"} {
+		e.encString(field)
+	}
+	e.buffer = append(e.buffer, BC_OBJECT_DIRECT)
+	e.encString("bad state")
+	e.buffer = append(e.buffer, BC_REF, 0x90) // cause is itself
+	e.buffer = append(e.buffer, BC_LIST_DIRECT_UNTYPED+1, BC_OBJECT_DEF)
+	e.encString("java.lang.StackTraceElement")
+	e.encInt32(4)
+	for _, field := range []string{"declaringClass", "methodName", "fileName", "lineNumber"} {
+		e.encString(field)
+	}
+	e.buffer = append(e.buffer, BC_OBJECT_DIRECT+1)
+	e.encString("com.foo.Bar")
+	e.encString("baz")
+	e.encString("Bar.java")
+	e.encInt32(10)
+	e.encNull()
+
+	b, _ := packDubbo(DubboHeader{Serialization: HESSIAN2_SERIALIZATION_ID, Status: DUBBO_OK, ID: 1}, e.Buffer())
+	pkg, _, err := DecodeDubboPacket(b)
+	if err != nil {
+		t.Fatalf("DecodeDubboPacket() = error:%v", err)
+	}
+	ex, ok := pkg.(*DubboResponse).Exception.(*DubboException)
+	if !ok {
+		t.Fatalf("DecodeDubboPacket() = %#v", pkg)
+	}
+	if ex.Error() != "java.lang.IllegalStateException: bad state" || ex.Cause != nil {
+		t.Errorf("exception = %#v", ex)
+	}
+	if !reflect.DeepEqual(ex.StackTrace, []string{"com.foo.Bar.baz(Bar.java:10)"}) {
+		t.Errorf("exception stack trace = %v", ex.StackTrace)
+	}
+}
+
+func TestSplitDescriptor(t *testing.T) {
+	types, err := splitDescriptor("Ljava/lang/String;I[J[[Lcom/foo/Bar;Z")
+	want := []string{"Ljava/lang/String;", "I", "[J", "[[Lcom/foo/Bar;", "Z"}
+	if err != nil || !reflect.DeepEqual(types, want) {
+		t.Errorf("splitDescriptor() = %v, error:%v", types, err)
+	}
+	for _, desc := range []string{"Ljava/lang/String", "[", "X"} {
+		if _, err = splitDescriptor(desc); err == nil {
+			t.Errorf("splitDescriptor(%q) should fail", desc)
+		}
+	}
+}
+
+func TestDubboDecodeOptions(t *testing.T) {
+	var (
+		r      = NewPOJORegistry()
+		opts   = &DubboDecodeOptions{POJORegistry: r}
+		req    = &DubboRequest{ID: 1, TwoWay: true, Path: "com.foo.Garage", Method: "Park", Args: []Any{Van{Name: "a"}}}
+		b, err = EncodeDubboRequest(req)
+		pkg    Any
+	)
+	if err != nil {
+		t.Fatalf("EncodeDubboRequest() = error:%v", err)
+	}
+
+	r.RegisterPOJO(Van{})
+	if pkg, err = ReadDubboPacketWithOptions(bytes.NewReader(b), opts); err != nil {
+		t.Fatalf("ReadDubboPacketWithOptions() = error:%v", err)
+	}
+	if args := pkg.(*DubboRequest).Args; !reflect.DeepEqual(args, []Any{&Van{Name: "a"}}) {
+		t.Errorf("ReadDubboPacketWithOptions() args = %#v", args)
+	}
+	if pkg, _, err = DecodeDubboPacketWithOptions(b, nil); err != nil {
+		t.Fatalf("DecodeDubboPacketWithOptions(nil) = error:%v", err)
+	}
+	if args := pkg.(*DubboRequest).Args; !reflect.DeepEqual(args, []Any{map[Any]Any{"name": "a"}}) {
+		t.Errorf("DecodeDubboPacketWithOptions(nil) args = %#v", args)
+	}
+}
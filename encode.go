@@ -12,8 +12,8 @@ package hessian
 
 import (
 	"bytes"
+	"math/big"
 	"reflect"
-	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -33,7 +33,36 @@ string []byte []interface{} map[interface{}]interface{}
 array object struct
 */
 
+// Encoder encodes values with options. The package level Encode uses the
+// default options.
 type Encoder struct {
+	deterministic bool // sort the map keys
+}
+
+var defaultEncoder = &Encoder{}
+
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// SetDeterministic makes the encoder sort the map keys and the set items by
+// their encoded bytes, so the same value is always encoded to the same bytes.
+// The fields of struct are always encoded in the order of their names, and
+// *OrderedMap is always encoded in insertion order.
+func (this *Encoder) SetDeterministic(deterministic bool) {
+	this.deterministic = deterministic
+}
+
+// keyEncoder returns the function to encode the map keys for sorting, or
+// nil if the map keys should not be sorted.
+func (this *Encoder) keyEncoder() func(Any) []byte {
+	if !this.deterministic {
+		return nil
+	}
+
+	return func(k Any) []byte {
+		return this.Encode(k, nil)
+	}
 }
 
 const (
@@ -41,8 +70,68 @@ const (
 	ENCODER_DEBUG = false
 )
 
-// If @v can not be encoded, the return value is nil. At present only struct may can not be encoded.
+const (
+	// fault codes used by com.caucho.hessian.server.HessianSkeleton
+	FAULT_NO_SUCH_METHOD = "NoSuchMethodException"
+	FAULT_SERVICE        = "ServiceException"
+	FAULT_PROTOCOL       = "ProtocolException"
+
+	// the go error in a fault is sent to java client as this exception
+	JAVA_SERVICE_EXCEPTION = "com.caucho.hessian.client.HessianServiceException"
+)
+
+// Fault is a hessian fault.
+// fault ::= f (string object)* z
+type Fault struct {
+	Code    string
+	Message string
+	Detail  Any
+}
+
+func (this *Fault) Error() string {
+	return fmt.Sprintf("%s : %s", this.Code, this.Message)
+}
+
+// newFault converts a go error to a fault whose detail is a java exception.
+func newFault(err error) *Fault {
+	if fault, ok := err.(*Fault); ok {
+		return fault
+	}
+
+	return &Fault{
+		Code:    FAULT_SERVICE,
+		Message: err.Error(),
+		Detail:  javaException{code: FAULT_SERVICE, message: err.Error()},
+	}
+}
+
+// javaException is a com.caucho.hessian.client.HessianServiceException.
+// java client(HessianProxy) throws the detail of a fault if it is a Throwable.
+type javaException struct {
+	code    string
+	message string
+}
+
+func (e javaException) GetType() string {
+	return JAVA_SERVICE_EXCEPTION
+}
+
+// java.lang.Throwable:detailMessage
+func (e javaException) GetDetailMessage() string {
+	return e.message
+}
+
+func (e javaException) GetCode() string {
+	return e.code
+}
+
+// If @v can not be encoded, the return value is nil. At present only struct, TypedMap and java enum may can not be encoded.
 func Encode(v interface{}, b []byte) []byte {
+	return defaultEncoder.Encode(v, b)
+}
+
+// Encode appends the hessian bytes of @v to @b like the package level Encode.
+func (this *Encoder) Encode(v interface{}, b []byte) []byte {
 	switch v.(type) {
 	case nil:
 		return encNull(b)
@@ -59,6 +148,24 @@ func Encode(v interface{}, b []byte) []byte {
 		// 把int统一按照int64处理，这样才不会导致decode的时候出现" reflect: Call using int32 as type int64 [recovered]"这种panic
 		b = encInt64(int64(v.(int)), b)
 
+	case JavaChar:
+		b = encString(v.(JavaChar).String(), b)
+
+	case []JavaChar:
+		b = encString(javaCharsString(v.([]JavaChar)), b)
+
+	case JavaByte:
+		b = encInt32(int32(v.(JavaByte)), b)
+
+	case JavaShort:
+		b = encInt32(int32(v.(JavaShort)), b)
+
+	case int8:
+		b = encInt32(int32(v.(int8)), b)
+
+	case int16:
+		b = encInt32(int32(v.(int16)), b)
+
 	case int32:
 		b = encInt32(v.(int32), b)
 
@@ -68,6 +175,12 @@ func Encode(v interface{}, b []byte) []byte {
 	case time.Time:
 		b = encDate(v.(time.Time), b)
 
+	case time.Duration:
+		b = this.encStruct(NewDuration(v.(time.Duration)), b)
+
+	case float32:
+		b = encFloat(float64(v.(float32)), b)
+
 	case float64:
 		b = encFloat(v.(float64), b)
 
@@ -78,13 +191,55 @@ func Encode(v interface{}, b []byte) []byte {
 		b = encBinary(v.([]byte), b)
 
 	case []Any:
-		b = encList(v.([]Any), b)
+		b = this.encList(v.([]Any), b)
 
 	case map[Any]Any:
-		b = encMap(v.(map[Any]Any), b)
+		b = this.encMap(v.(map[Any]Any), b)
+
+	case TypedMap:
+		var m = v.(TypedMap)
+		b = this.encTypedMap(&m, b)
+
+	case *TypedMap:
+		b = this.encTypedMap(v.(*TypedMap), b)
+
+	case Object:
+		var o = v.(Object)
+		b = this.encUnknownObject(&o, b)
+
+	case *Object:
+		b = this.encUnknownObject(v.(*Object), b)
+
+	case *big.Int:
+		if v.(*big.Int) == nil {
+			return encNull(b)
+		}
+		b = this.encStruct(NewBigInteger(v.(*big.Int)), b)
+
+	case *big.Float:
+		if v.(*big.Float) == nil {
+			return encNull(b)
+		}
+		b = this.encStruct(NewBigDecimal(v.(*big.Float)), b)
 
 	default:
+		if m, ok, err := marshal(v); ok {
+			if err != nil {
+				log.Error("%s", err)
+				return nil
+			}
+			b = this.Encode(m, b)
+			break
+		}
 		t := reflect.TypeOf(v)
+		if e, ok := getJavaEnumOf(t); ok {
+			b = this.encEnum(e, v, b)
+			break
+		}
+		if name, ok := getCollectionName(t); ok {
+			b = this.encCollection(name, reflect.ValueOf(v), b)
+			break
+		}
 		if reflect.Ptr == t.Kind() {
 			// tmp := reflect.ValueOf(v).Elem()
 			// t = reflect.TypeOf(tmp)
@@ -92,12 +247,24 @@ func Encode(v interface{}, b []byte) []byte {
 		}
 		switch t.Kind() {
 		case reflect.Struct:
-			b = encStruct(v, b)
+			b = this.encStruct(v, b)
 		case reflect.Slice, reflect.Array:
-			b = encList(v.([]Any), b)
+			// java array, such as []int32 -> [int
+			if name, ok := getArrayName(t); ok {
+				b = this.encCollection(name, reflect.ValueOf(v), b)
+				break
+			}
+			var (
+				value = reflect.ValueOf(v)
+				list  = make([]Any, value.Len())
+			)
+			for i := 0; i < value.Len(); i++ {
+				list[i] = value.Index(i).Interface()
+			}
+			b = this.encList(list, b)
 		case reflect.Map: // 进入这个case，就说明map可能是map[string]int这种类型
-			// b = encMap(v, b)
-			b = encMapByReflect(v, b)
+			// b = this.encMap(v, b)
+			b = this.encMapByReflect(v, b)
 		default:
 			log.Debug("type not Support! %s", t.Kind().String())
 			panic("unknow type")
@@ -111,6 +278,41 @@ func Encode(v interface{}, b []byte) []byte {
 	return b
 }
 
+//=====================================
+// hessian reply
+//=====================================
+
+// EncodeReply appends a valid reply of @v to @b.
+// valid-reply ::= r x01 x00 header* object z
+func EncodeReply(v Any, b []byte) []byte {
+	b = append(b, 'r', 1, 0)
+	b = Encode(v, b)
+	return append(b, 'z')
+}
+
+// EncodeFault appends a fault reply of @err to @b.
+// If @err is a *Fault, its code, message and detail are sent as they are;
+// otherwise its code is "ServiceException" and its detail is a
+// com.caucho.hessian.client.HessianServiceException, which will be thrown
+// by java client.
+// fault-reply ::= r x01 x00 header* fault z
+// fault       ::= f (string object)* z
+func EncodeFault(err error, b []byte) []byte {
+	var fault = newFault(err)
+
+	b = append(b, 'r', 1, 0, 'f')
+	b = encString("code", b)
+	b = encString(fault.Code, b)
+	b = encString("message", b)
+	b = encString(fault.Message, b)
+	if fault.Detail != nil {
+		b = encString("detail", b)
+		b = Encode(fault.Detail, b)
+	}
+
+	return append(b, 'z', 'z')
+}
+
 //=====================================
 //对各种数据类型的编码
 //=====================================
@@ -245,7 +447,7 @@ func encBinary(v []byte, b []byte) []byte {
 }
 
 // list
-func encList(v []Any, b []byte) []byte {
+func (this *Encoder) encList(v []Any, b []byte) []byte {
 	b = append(b, 'V')
 
 	b = append(b, 'l')
@@ -253,7 +455,7 @@ func encList(v []Any, b []byte) []byte {
 	b = append(b, PackInt32(int32(len(v)))...)
 
 	for _, a := range v {
-		b = Encode(a, b)
+		b = this.Encode(a, b)
 	}
 
 	b = append(b, 'z')
@@ -261,17 +463,19 @@ func encList(v []Any, b []byte) []byte {
 	return b
 }
 
-// map
-func encMap(m map[Any]Any, b []byte) []byte {
-	if len(m) == 0 {
-		return b
-	}
-
+// map ::= M (object object)* z
+// the empty map is encoded as M z to keep its place in the call arguments
+func (this *Encoder) encMap(m map[Any]Any, b []byte) []byte {
 	b = append(b, 'M')
 
-	for k, v := range m {
-		b = Encode(k, b)
-		b = Encode(v, b)
+	var keys = make([]Any, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sortItems(keys, nil, this.keyEncoder())
+	for _, k := range keys {
+		b = this.Encode(k, b)
+		b = this.Encode(m[k], b)
 	}
 
 	b = append(b, 'z')
@@ -279,6 +483,94 @@ func encMap(m map[Any]Any, b []byte) []byte {
 	return b
 }
 
+// type ::= t b16 b8 type-string
+func encType(typ string, b []byte) []byte {
+	b = append(b, 't')
+	b = append(b, PackUint16(uint16(utf8.RuneCountInString(typ)))...)
+	return append(b, typ...)
+}
+
+// the list or map of registered java collection class @typ
+// list ::= V type? length? object* z
+// map  ::= M type? (object object)* z
+func (this *Encoder) encCollection(typ string, v reflect.Value, b []byte) []byte {
+	var list, m = collectionItems(v, this.keyEncoder())
+
+	if list != nil {
+		b = append(b, 'V')
+		b = encType(typ, b)
+		b = append(b, 'l')
+		b = append(b, PackInt32(int32(len(list)))...)
+		for _, a := range list {
+			b = this.Encode(a, b)
+		}
+		return append(b, 'z')
+	}
+
+	return this.encMapEntries(typ, m, b)
+}
+
+// the map of java class @v.Type
+// map ::= M type? (object object)* z
+func (this *Encoder) encTypedMap(v *TypedMap, b []byte) []byte {
+	if v == nil {
+		return encNull(b)
+	}
+
+	var m, err = typedMapItems(v, this.keyEncoder())
+	if err != nil {
+		log.Error("%s", err)
+		return nil
+	}
+
+	return this.encMapEntries(v.Type, m, b)
+}
+
+// the object of unknown java class
+// map ::= M type (object object)* z
+func (this *Encoder) encUnknownObject(v *Object, b []byte) []byte {
+	if v == nil {
+		return encNull(b)
+	}
+	if v.Fields == nil {
+		return this.encMapEntries(v.Type, NewOrderedMap(), b)
+	}
+
+	return this.encMapEntries(v.Type, v.Fields, b)
+}
+
+// the constant @v of java enum @e
+// map ::= M type string string z
+func (this *Encoder) encEnum(e *javaEnum, v Any, b []byte) []byte {
+	var name, err = e.name(v)
+	if err != nil {
+		log.Error("%s", err)
+		return nil
+	}
+
+	b = append(b, 'M')
+	b = encType(e.javaType, b)
+	b = encString(JAVA_ENUM_NAME_FIELD, b)
+	b = encString(name, b)
+
+	return append(b, 'z')
+}
+
+// map ::= M type? (object object)* z
+func (this *Encoder) encMapEntries(typ string, m *OrderedMap, b []byte) []byte {
+	b = append(b, 'M')
+	if typ != "" {
+		b = encType(typ, b)
+	}
+	m.Range(func(k, v Any) bool {
+		b = this.Encode(k, b)
+		b = this.Encode(v, b)
+		return true
+	})
+
+	return append(b, 'z')
+}
+
 func buildMapKey(key reflect.Value, typ reflect.Type) interface{} {
 	switch typ.Kind() {
 	case reflect.String:
@@ -290,6 +582,7 @@ func buildMapKey(key reflect.Value, typ reflect.Type) interface{} {
 	case reflect.Int8:
 		return int8(key.Int())
 	case reflect.Int16:
+		return int16(key.Int())
 	case reflect.Int32:
 		return int32(key.Int())
 	case reflect.Int64:
@@ -304,28 +597,34 @@ func buildMapKey(key reflect.Value, typ reflect.Type) interface{} {
 	// return newCodecError("unsuport key kind " + typ.Kind().String())
 }
 
-func encMapByReflect(m interface{}, b []byte) []byte {
+func (this *Encoder) encMapByReflect(m interface{}, b []byte) []byte {
 	var (
-		buf   []byte // 如果map encode失败，也不会影响b中已有的内容
-		typ   reflect.Type
-		value reflect.Value
-		keys  []reflect.Value
+		buf    []byte // 如果map encode失败，也不会影响b中已有的内容
+		typ    reflect.Type
+		value  reflect.Value
+		keys   []reflect.Value
+		ks, vs []Any
 	)
 
 	buf = append(buf, 'M')
 	value = reflect.ValueOf(m)
 	typ = reflect.TypeOf(m).Key()
-	keys = value.MapKeys()
-	if len(keys) == 0 {
-		return b
+	if value.IsNil() {
+		return encNull(b)
 	}
+	keys = value.MapKeys()
 	for i := 0; i < len(keys); i++ {
 		k := buildMapKey(keys[i], typ)
 		if k == nil {
 			return b
 		}
-		buf = Encode(k, buf)
-		buf = Encode(value.MapIndex(keys[i]).Interface(), buf)
+		ks = append(ks, k)
+		vs = append(vs, value.MapIndex(keys[i]).Interface())
+	}
+	sortItems(ks, vs, this.keyEncoder())
+	for i := range ks {
+		buf = this.Encode(ks[i], buf)
+		buf = this.Encode(vs[i], buf)
 	}
 	buf = append(buf, 'z')
 
@@ -336,73 +635,43 @@ func encMapByReflect(m interface{}, b []byte) []byte {
 // attention list:
 // @v should have method "GetType" which return @v struct name
 // @v should have method "Get..." to get its member value
-func encStruct(v Any, b []byte) []byte {
+func (this *Encoder) encStruct(v Any, b []byte) []byte {
 	var (
-		i          int
-		l          int
-		length     int
-		str        string
-		buf        *bytes.Buffer
-		vT         reflect.Type
-		vV         reflect.Value
-		methodType reflect.Value
-		typeName   reflect.Value
-		method     reflect.Method
-		rvArray    []reflect.Value
+		l      int
+		length int
+		ok     bool
+		pojo   POJO
+		value  Any
+		vV     reflect.Value
+		plan   *pojoPlan
 	)
 
 	// check Type exists
 	// mast contains Type Field to convert to object
-	vV = reflect.ValueOf(v)
-	methodType = vV.MethodByName("GetType")
-	if !methodType.IsValid() {
+	if pojo, ok = v.(POJO); !ok {
 		log.Error("Don'T contains GetType !")
 		return nil
 	}
 
 	b = append(b, 'M')
-	//encode type Name
-	b = append(b, 't')
 	// encode struct name
-	typeName = methodType.Call([]reflect.Value{})[0] //call return [string,]
-	buf = bytes.NewBufferString(typeName.String())
-	length = utf8.RuneCount(buf.Bytes())
-	b = append(b, PackUint16(uint16(length))...)
-	for i = 0; i < length; i++ {
-		if r, s, err := buf.ReadRune(); s > 0 && err == nil {
-			// b = append(b, []byte(string(r))...)
-			b = append(b, gxstrings.Slice(string(r))...) // 直接基于r的内存空间把它转换为[]byte
-		}
-	}
+	b = encType(pojo.GetType(), b)
 
 	//encode the Fields
-	vT = reflect.TypeOf(v)
-	for i = 0; i < vT.NumMethod(); i++ {
-		method = vT.Method(i)
-		if !strings.HasPrefix(method.Name, "Get") {
-			continue
-		}
-		if strings.EqualFold(method.Name, "GetType") {
-			continue //jump type Field
-		}
-
-		//name change GetXaa to xaa
-		if method.Name[3] < 'a' {
-			str = string(method.Name[3] + 32)
-		} else {
-			str = string(method.Name[3])
-		}
+	vV = reflect.ValueOf(v)
+	plan = getPOJOPlan(vV.Type())
+	for i, name := range plan.names {
 		// key
 		l = len(b)
-		b = encString(str+method.Name[4:], b)
+		b = encString(name, b)
 		length = len(b)
 
 		// value
-		rvArray = vV.Method(i).Call([]reflect.Value{}) //return [] reflect.Value
-		b = Encode(rvArray[0].Interface(), b)          //GetXXX returns [string,]
+		value = vV.Method(plan.getters[i]).Call(nil)[0].Interface() //GetXXX returns [string,]
+		b = this.Encode(value, b)
 		// 如果值为空就不向b里面填充key了
 		if len(b) == length {
-			fmt.Printf("key:%s, rvArray:%#v, %v, %v, %v\n", str+method.Name[4:], rvArray, rvArray == nil, len(rvArray), rvArray[0])
+			log.Debug("key:%s, value:%#v", name, value)
 			b = b[:l]
 			continue
 		}
@@ -0,0 +1,765 @@
+/******************************************************
+# DESC    : hessian 2.0 encode
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 11:05
+# FILE    : encode2.go
+******************************************************/
+
+// refers to http://hessian.caucho.com/doc/hessian-serialization.html
+// and com.caucho.hessian.io.Hessian2Output
+
+package hessian
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	BC_BINARY         = byte('B')  // final chunk
+	BC_BINARY_CHUNK   = byte('A')  // non-final chunk
+	BC_BINARY_DIRECT  = byte(0x20) // 1-byte length binary
+	BINARY_DIRECT_MAX = 0x0f
+	BC_BINARY_SHORT   = byte(0x34) // 2-byte length binary
+	BINARY_SHORT_MAX  = 0x3ff      // 0-1023 binary
+
+	BC_DATE        = byte(0x4a) // 64-bit millisecond UTC date
+	BC_DATE_MINUTE = byte(0x4b) // 32-bit minute UTC date
+
+	BC_DOUBLE       = byte('D')
+	BC_DOUBLE_ZERO  = byte(0x5b)
+	BC_DOUBLE_ONE   = byte(0x5c)
+	BC_DOUBLE_BYTE  = byte(0x5d)
+	BC_DOUBLE_SHORT = byte(0x5e)
+	BC_DOUBLE_MILL  = byte(0x5f)
+
+	BC_FALSE = byte('F') // boolean false
+	BC_TRUE  = byte('T') // boolean true
+
+	BC_INT            = byte('I') // 32-bit int
+	INT_DIRECT_MIN    = -0x10
+	INT_DIRECT_MAX    = 0x2f
+	BC_INT_ZERO       = byte(0x90)
+	INT_BYTE_MIN      = -0x800
+	INT_BYTE_MAX      = 0x7ff
+	BC_INT_BYTE_ZERO  = byte(0xc8)
+	INT_SHORT_MIN     = -0x40000
+	INT_SHORT_MAX     = 0x3ffff
+	BC_INT_SHORT_ZERO = byte(0xd4)
+
+	BC_LIST_VARIABLE         = byte(0x55)
+	BC_LIST_FIXED            = byte('V')
+	BC_LIST_VARIABLE_UNTYPED = byte(0x57)
+	BC_LIST_FIXED_UNTYPED    = byte(0x58)
+	BC_LIST_DIRECT           = byte(0x70)
+	BC_LIST_DIRECT_UNTYPED   = byte(0x78)
+	LIST_DIRECT_MAX          = 0x7
+
+	BC_LONG            = byte('L') // 64-bit signed integer
+	LONG_DIRECT_MIN    = -0x08
+	LONG_DIRECT_MAX    = 0x0f
+	BC_LONG_ZERO       = byte(0xe0)
+	LONG_BYTE_MIN      = -0x800
+	LONG_BYTE_MAX      = 0x7ff
+	BC_LONG_BYTE_ZERO  = byte(0xf8)
+	LONG_SHORT_MIN     = -0x40000
+	LONG_SHORT_MAX     = 0x3ffff
+	BC_LONG_SHORT_ZERO = byte(0x3c)
+	BC_LONG_INT        = byte(0x59)
+
+	BC_MAP         = byte('M')
+	BC_MAP_UNTYPED = byte('H')
+
+	BC_NULL = byte('N')
+
+	BC_OBJECT         = byte('O')
+	BC_OBJECT_DEF     = byte('C')
+	BC_OBJECT_DIRECT  = byte(0x60)
+	OBJECT_DIRECT_MAX = 0x0f
+
+	BC_REF = byte(0x51)
+
+	BC_END = byte('Z')
+
+	BC_STRING         = byte('S') // final string
+	BC_STRING_CHUNK   = byte('R') // non-final string
+	BC_STRING_DIRECT  = byte(0x00)
+	STRING_DIRECT_MAX = 0x1f
+	BC_STRING_SHORT   = byte(0x30)
+	STRING_SHORT_MAX  = 0x3ff
+
+	// message envelope
+	BC_VERSION = byte('H') // H x02 x00
+	BC_REPLY   = byte('R')
+	BC_FAULT   = byte('F')
+	BC_CALL    = byte('C')
+)
+
+// Encoder2 encodes go values as hessian 2.0 values.
+// The class definitions and the types of typed lists and maps are written
+// only once by an Encoder2 and are referenced by index afterwards, so all the
+// values encoded by an Encoder2 should be decoded by one decoder.
+type Encoder2 struct {
+	buffer        []byte
+	classes       map[string]int // class definition index
+	types         map[string]int // type index of typed list and map
+	deterministic bool           // sort the map keys
+}
+
+func NewEncoder2() *Encoder2 {
+	return &Encoder2{
+		classes: make(map[string]int),
+		types:   make(map[string]int),
+	}
+}
+
+// Buffer returns all the bytes that has been encoded.
+func (this *Encoder2) Buffer() []byte {
+	return this.buffer
+}
+
+// SetDeterministic makes the encoder sort the map keys and the set items by
+// their encoded bytes, so the same value is always encoded to the same bytes.
+// The fields of POJO are always encoded in the order of their names, and
+// *OrderedMap is always encoded in insertion order.
+func (this *Encoder2) SetDeterministic(deterministic bool) {
+	this.deterministic = deterministic
+}
+
+// keyEncoder returns the function to encode the map keys for sorting, or
+// nil if the map keys should not be sorted. A key is encoded by a new
+// Encoder2, so its bytes do not depend on the class definitions before it.
+func (this *Encoder2) keyEncoder() func(Any) []byte {
+	if !this.deterministic {
+		return nil
+	}
+
+	return func(k Any) []byte {
+		var e = NewEncoder2()
+		e.deterministic = true
+		e.Encode(k)
+		return e.Buffer()
+	}
+}
+
+// Reset clears the buffer and the class definitions.
+func (this *Encoder2) Reset() {
+	this.buffer = this.buffer[:0]
+	this.classes = make(map[string]int)
+	this.types = make(map[string]int)
+}
+
+// Encode appends the hessian 2.0 encoding of @v to its buffer.
+// If @v can not be encoded, the buffer is not changed.
+func (this *Encoder2) Encode(v Any) error {
+	var (
+		err error
+		l   = len(this.buffer)
+	)
+
+	if err = this.encode(v); err != nil {
+		this.buffer = this.buffer[:l]
+	}
+
+	return err
+}
+
+func (this *Encoder2) encode(v Any) error {
+	switch v.(type) {
+	case nil:
+		this.encNull()
+
+	case bool:
+		this.encBool(v.(bool))
+
+	case int:
+		// the same as Encode, int is encoded as long
+		this.encInt64(int64(v.(int)))
+
+	case JavaChar:
+		this.encString(v.(JavaChar).String())
+
+	case []JavaChar:
+		this.encString(javaCharsString(v.([]JavaChar)))
+
+	case JavaByte:
+		this.encInt32(int32(v.(JavaByte)))
+
+	case JavaShort:
+		this.encInt32(int32(v.(JavaShort)))
+
+	case int8:
+		this.encInt32(int32(v.(int8)))
+
+	case int16:
+		this.encInt32(int32(v.(int16)))
+
+	case int32:
+		this.encInt32(v.(int32))
+
+	case int64:
+		this.encInt64(v.(int64))
+
+	case time.Time:
+		this.encDate(v.(time.Time))
+
+	case time.Duration:
+		return this.encObject(NewDuration(v.(time.Duration)))
+
+	case float32:
+		this.encFloat(float64(v.(float32)))
+
+	case float64:
+		this.encFloat(v.(float64))
+
+	case string:
+		this.encString(v.(string))
+
+	case []byte:
+		this.encBinary(v.([]byte))
+
+	case []Any:
+		return this.encList(v.([]Any))
+
+	case map[Any]Any:
+		return this.encMap(v.(map[Any]Any))
+
+	case TypedMap:
+		var m = v.(TypedMap)
+		return this.encTypedMap(&m)
+
+	case *TypedMap:
+		return this.encTypedMap(v.(*TypedMap))
+
+	case Object:
+		var o = v.(Object)
+		return this.encUnknownObject(&o)
+
+	case *Object:
+		return this.encUnknownObject(v.(*Object))
+
+	case *big.Int:
+		if v.(*big.Int) == nil {
+			this.encNull()
+			return nil
+		}
+		return this.encObject(NewBigInteger(v.(*big.Int)))
+
+	case *big.Float:
+		if v.(*big.Float) == nil {
+			this.encNull()
+			return nil
+		}
+		return this.encObject(NewBigDecimal(v.(*big.Float)))
+
+	default:
+		return this.encByReflect(v)
+	}
+
+	return nil
+}
+
+func (this *Encoder2) encByReflect(v Any) error {
+	var value = reflect.ValueOf(v)
+
+	if m, ok, err := marshal(v); ok {
+		if err != nil {
+			return err
+		}
+		return this.Encode(m)
+	}
+	if _, ok := v.(POJO); ok {
+		return this.encObject(v)
+	}
+	if e, ok := getJavaEnumOf(value.Type()); ok {
+		var name, err = e.name(v)
+		if err != nil {
+			return err
+		}
+		return this.encObjectFields(e.javaType, []string{JAVA_ENUM_NAME_FIELD}, []Any{name})
+	}
+	if name, ok := getCollectionName(value.Type()); ok {
+		return this.encCollection(name, value)
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			this.encNull()
+			return nil
+		}
+		return this.encode(value.Elem().Interface())
+
+	case reflect.Slice, reflect.Array:
+		// java array, such as []int32 -> [int
+		if name, ok := getArrayName(value.Type()); ok {
+			return this.encCollection(name, value)
+		}
+		var list = make([]Any, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			list[i] = value.Index(i).Interface()
+		}
+		return this.encList(list)
+
+	case reflect.Map:
+		if value.IsNil() {
+			this.encNull()
+			return nil
+		}
+		var keys, values = mapItems(value)
+		sortItems(keys, values, this.keyEncoder())
+		this.buffer = append(this.buffer, BC_MAP_UNTYPED)
+		for i := range keys {
+			if err := this.encode(keys[i]); err != nil {
+				return err
+			}
+			if err := this.encode(values[i]); err != nil {
+				return err
+			}
+		}
+		this.buffer = append(this.buffer, BC_END)
+		return nil
+	}
+
+	return fmt.Errorf("type not supported! %s", value.Type())
+}
+
+//=====================================
+//对各种数据类型的编码
+//=====================================
+
+// null ::= N
+func (this *Encoder2) encNull() {
+	this.buffer = append(this.buffer, BC_NULL)
+}
+
+// boolean ::= T
+//
+//	::= F
+func (this *Encoder2) encBool(v bool) {
+	var c = BC_FALSE
+	if v {
+		c = BC_TRUE
+	}
+
+	this.buffer = append(this.buffer, c)
+}
+
+// int ::= 'I' b3 b2 b1 b0
+//
+//	::= [x80-xbf]
+//	::= [xc0-xcf] b0
+//	::= [xd0-xd7] b1 b0
+func (this *Encoder2) encInt32(v int32) {
+	if INT_DIRECT_MIN <= v && v <= INT_DIRECT_MAX {
+		this.buffer = append(this.buffer, byte(int32(BC_INT_ZERO)+v))
+	} else if INT_BYTE_MIN <= v && v <= INT_BYTE_MAX {
+		this.buffer = append(this.buffer, byte(int32(BC_INT_BYTE_ZERO)+(v>>8)), byte(v))
+	} else if INT_SHORT_MIN <= v && v <= INT_SHORT_MAX {
+		this.buffer = append(this.buffer, byte(int32(BC_INT_SHORT_ZERO)+(v>>16)), byte(v>>8), byte(v))
+	} else {
+		this.buffer = append(this.buffer, BC_INT)
+		this.buffer = append(this.buffer, PackInt32(v)...)
+	}
+}
+
+// long ::= L b7 b6 b5 b4 b3 b2 b1 b0
+//
+//	::= [xd8-xef]
+//	::= [xf0-xff] b0
+//	::= [x38-x3f] b1 b0
+//	::= x59 b3 b2 b1 b0
+func (this *Encoder2) encInt64(v int64) {
+	if LONG_DIRECT_MIN <= v && v <= LONG_DIRECT_MAX {
+		this.buffer = append(this.buffer, byte(int64(BC_LONG_ZERO)+v))
+	} else if LONG_BYTE_MIN <= v && v <= LONG_BYTE_MAX {
+		this.buffer = append(this.buffer, byte(int64(BC_LONG_BYTE_ZERO)+(v>>8)), byte(v))
+	} else if LONG_SHORT_MIN <= v && v <= LONG_SHORT_MAX {
+		this.buffer = append(this.buffer, byte(int64(BC_LONG_SHORT_ZERO)+(v>>16)), byte(v>>8), byte(v))
+	} else if math.MinInt32 <= v && v <= math.MaxInt32 {
+		this.buffer = append(this.buffer, BC_LONG_INT)
+		this.buffer = append(this.buffer, PackInt32(int32(v))...)
+	} else {
+		this.buffer = append(this.buffer, BC_LONG)
+		this.buffer = append(this.buffer, PackInt64(v)...)
+	}
+}
+
+// date ::= x4a b7 b6 b5 b4 b3 b2 b1 b0
+//
+//	::= x4b b3 b2 b1 b0       # minutes since epoch
+func (this *Encoder2) encDate(v time.Time) {
+	var ms = v.UnixNano() / 1e6
+	if ms%60000 == 0 && math.MinInt32 <= ms/60000 && ms/60000 <= math.MaxInt32 {
+		this.buffer = append(this.buffer, BC_DATE_MINUTE)
+		this.buffer = append(this.buffer, PackInt32(int32(ms/60000))...)
+		return
+	}
+
+	this.buffer = append(this.buffer, BC_DATE)
+	this.buffer = append(this.buffer, PackInt64(ms)...)
+}
+
+// double ::= D b7 b6 b5 b4 b3 b2 b1 b0
+//
+//	::= x5b
+//	::= x5c
+//	::= x5d b0
+//	::= x5e b1 b0
+//	::= x5f b3 b2 b1 b0     # double in thousandths(Hessian2Output:BC_DOUBLE_MILL)
+func (this *Encoder2) encFloat(v float64) {
+	if math.MinInt32 <= v && v <= math.MaxInt32 && float64(int32(v)) == v {
+		var i = int32(v)
+		switch {
+		case i == 0:
+			this.buffer = append(this.buffer, BC_DOUBLE_ZERO)
+			return
+		case i == 1:
+			this.buffer = append(this.buffer, BC_DOUBLE_ONE)
+			return
+		case -0x80 <= i && i < 0x80:
+			this.buffer = append(this.buffer, BC_DOUBLE_BYTE, byte(i))
+			return
+		case -0x8000 <= i && i < 0x8000:
+			this.buffer = append(this.buffer, BC_DOUBLE_SHORT)
+			this.buffer = append(this.buffer, PackInt16(int16(i))...)
+			return
+		}
+	}
+
+	if math.MinInt32 <= v*1000 && v*1000 <= math.MaxInt32 {
+		var mills = int32(v * 1000)
+		if 0.001*float64(mills) == v {
+			this.buffer = append(this.buffer, BC_DOUBLE_MILL)
+			this.buffer = append(this.buffer, PackInt32(mills)...)
+			return
+		}
+	}
+
+	this.buffer = append(this.buffer, BC_DOUBLE)
+	this.buffer = append(this.buffer, PackFloat64(v)...)
+}
+
+// string ::= x52 b1 b0 <utf8-data> string
+//
+//	::= S b1 b0 <utf8-data>
+//	::= [x00-x1f] <utf8-data>
+//	::= [x30-x33] b0 <utf8-data>
+//
+// the length is the count of java chars(utf16), and the chars out of the
+// BMP are written as two 3-byte surrogates like java.
+func (this *Encoder2) encString(v string) {
+	var chars = utf16.Encode([]rune(v))
+
+	for len(chars) > CHUNK_SIZE {
+		var n = CHUNK_SIZE
+		if utf16.IsSurrogate(rune(chars[n-1])) && chars[n-1] < 0xdc00 { // do not split a surrogate pair
+			n--
+		}
+		this.buffer = append(this.buffer, BC_STRING_CHUNK)
+		this.buffer = append(this.buffer, PackUint16(uint16(n))...)
+		this.buffer = appendJavaChars(this.buffer, chars[:n])
+		chars = chars[n:]
+	}
+
+	switch l := len(chars); {
+	case l <= STRING_DIRECT_MAX:
+		this.buffer = append(this.buffer, BC_STRING_DIRECT+byte(l))
+	case l <= STRING_SHORT_MAX:
+		this.buffer = append(this.buffer, BC_STRING_SHORT+byte(l>>8), byte(l))
+	default:
+		this.buffer = append(this.buffer, BC_STRING)
+		this.buffer = append(this.buffer, PackUint16(uint16(l))...)
+	}
+	this.buffer = appendJavaChars(this.buffer, chars)
+}
+
+// appendJavaChars encodes utf16 chars in the way of java, a surrogate is
+// encoded as a 3-byte utf8 sequence.
+func appendJavaChars(b []byte, chars []uint16) []byte {
+	for _, c := range chars {
+		switch {
+		case c < 0x80:
+			b = append(b, byte(c))
+		case c < 0x800:
+			b = append(b, byte(0xc0|(c>>6)), byte(0x80|(c&0x3f)))
+		default:
+			b = append(b, byte(0xe0|(c>>12)), byte(0x80|((c>>6)&0x3f)), byte(0x80|(c&0x3f)))
+		}
+	}
+
+	return b
+}
+
+// binary ::= x41 b1 b0 <binary-data> binary
+//
+//	::= x42 b1 b0 <binary-data>
+//	::= [x20-x2f] <binary-data>
+//	::= [x34-x37] b0 <binary-data>
+func (this *Encoder2) encBinary(v []byte) {
+	for len(v) > CHUNK_SIZE {
+		this.buffer = append(this.buffer, BC_BINARY_CHUNK)
+		this.buffer = append(this.buffer, PackUint16(uint16(CHUNK_SIZE))...)
+		this.buffer = append(this.buffer, v[:CHUNK_SIZE]...)
+		v = v[CHUNK_SIZE:]
+	}
+
+	switch l := len(v); {
+	case l <= BINARY_DIRECT_MAX:
+		this.buffer = append(this.buffer, BC_BINARY_DIRECT+byte(l))
+	case l <= BINARY_SHORT_MAX:
+		this.buffer = append(this.buffer, BC_BINARY_SHORT+byte(l>>8), byte(l))
+	default:
+		this.buffer = append(this.buffer, BC_BINARY)
+		this.buffer = append(this.buffer, PackUint16(uint16(l))...)
+	}
+	this.buffer = append(this.buffer, v...)
+}
+
+// list ::= x58 int value*        # fixed-length untyped list
+//
+//	::= [x78-7f] value*       # fixed untyped list with direct length
+func (this *Encoder2) encList(v []Any) error {
+	if len(v) <= LIST_DIRECT_MAX {
+		this.buffer = append(this.buffer, BC_LIST_DIRECT_UNTYPED+byte(len(v)))
+	} else {
+		this.buffer = append(this.buffer, BC_LIST_FIXED_UNTYPED)
+		this.encInt32(int32(len(v)))
+	}
+
+	for _, a := range v {
+		if err := this.encode(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// map ::= H (value value)* Z     # untyped key, value
+func (this *Encoder2) encMap(m map[Any]Any) error {
+	var keys = make([]Any, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sortItems(keys, nil, this.keyEncoder())
+
+	this.buffer = append(this.buffer, BC_MAP_UNTYPED)
+	for _, k := range keys {
+		if err := this.encode(k); err != nil {
+			return err
+		}
+		if err := this.encode(m[k]); err != nil {
+			return err
+		}
+	}
+	this.buffer = append(this.buffer, BC_END)
+
+	return nil
+}
+
+// the list or map of registered java collection class @typ
+// list ::= 'V' type int value*   # fixed-length list
+//      ::= [x70-77] type value*  # fixed-length typed list
+// map  ::= M type (value value)* Z
+func (this *Encoder2) encCollection(typ string, v reflect.Value) error {
+	var list, m = collectionItems(v, this.keyEncoder())
+
+	if list != nil {
+		if len(list) <= LIST_DIRECT_MAX {
+			this.buffer = append(this.buffer, BC_LIST_DIRECT+byte(len(list)))
+			this.encType(typ)
+		} else {
+			this.buffer = append(this.buffer, BC_LIST_FIXED)
+			this.encType(typ)
+			this.encInt32(int32(len(list)))
+		}
+		for _, a := range list {
+			if err := this.encode(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return this.encMapEntries(typ, m)
+}
+
+// the map of java class @v.Type
+// map ::= M type (value value)* Z
+func (this *Encoder2) encTypedMap(v *TypedMap) error {
+	if v == nil {
+		this.encNull()
+		return nil
+	}
+
+	var m, err = typedMapItems(v, this.keyEncoder())
+	if err != nil {
+		return err
+	}
+
+	return this.encMapEntries(v.Type, m)
+}
+
+// encUnknownObject encodes @v as an object of its java class, or a typed map
+// if some of its keys are not strings.
+func (this *Encoder2) encUnknownObject(v *Object) error {
+	if v == nil {
+		this.encNull()
+		return nil
+	}
+
+	var names, values, err = objectFields(v)
+	if err != nil {
+		return this.encMapEntries(v.Type, v.Fields)
+	}
+
+	return this.encObjectFields(v.Type, names, values)
+}
+
+// map ::= M type (value value)* Z
+//
+//	::= H (value value)* Z
+func (this *Encoder2) encMapEntries(typ string, m *OrderedMap) error {
+	var err error
+
+	if typ != "" {
+		this.buffer = append(this.buffer, BC_MAP)
+		this.encType(typ)
+	} else {
+		this.buffer = append(this.buffer, BC_MAP_UNTYPED)
+	}
+	m.Range(func(k, v Any) bool {
+		if err = this.encode(k); err == nil {
+			err = this.encode(v)
+		}
+		return err == nil
+	})
+	this.buffer = append(this.buffer, BC_END)
+
+	return err
+}
+
+// type ::= string                # type name
+//
+//	::= int                   # type reference
+func (this *Encoder2) encType(typ string) {
+	if idx, ok := this.types[typ]; ok {
+		this.encInt32(int32(idx))
+		return
+	}
+
+	this.types[typ] = len(this.types)
+	this.encString(typ)
+}
+
+// class-def ::= 'C' string int string*
+// object    ::= 'O' int value*
+//
+//	::= [x60-x6f] value*
+//
+// @v should be a POJO whose fields are got by its "Get..." methods like encStruct.
+func (this *Encoder2) encObject(v Any) error {
+	var typ, names, values = getPOJOFields(v)
+	return this.encObjectFields(typ, names, values)
+}
+
+// encObjectFields encodes an object of java class @typ whose fields are
+// @names and their values are @values.
+func (this *Encoder2) encObjectFields(typ string, names []string, values []Any) error {
+	var (
+		ok  bool
+		idx int
+	)
+
+	if idx, ok = this.classes[typ]; !ok {
+		idx = len(this.classes)
+		this.classes[typ] = idx
+		this.buffer = append(this.buffer, BC_OBJECT_DEF)
+		this.encString(typ)
+		this.encInt32(int32(len(names)))
+		for _, name := range names {
+			this.encString(name)
+		}
+	}
+
+	if idx <= OBJECT_DIRECT_MAX {
+		this.buffer = append(this.buffer, BC_OBJECT_DIRECT+byte(idx))
+	} else {
+		this.buffer = append(this.buffer, BC_OBJECT)
+		this.encInt32(int32(idx))
+	}
+	for _, value := range values {
+		if err := this.encode(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPOJOFields returns the java type name of @v and its field names and
+// values got by the "Get..." methods of @v, such as GetName -> name.
+// The field names are shared by the encoding plan of the type of @v.
+func getPOJOFields(v Any) (string, []string, []Any) {
+	var (
+		vV     = reflect.ValueOf(v)
+		plan   = getPOJOPlan(vV.Type())
+		values = make([]Any, len(plan.getters))
+	)
+
+	for i, idx := range plan.getters {
+		values[i] = vV.Method(idx).Call(nil)[0].Interface()
+	}
+
+	return v.(POJO).GetType(), plan.names, values
+}
+
+//=====================================
+// hessian 2.0 reply
+//=====================================
+
+// EncodeReply encodes a hessian 2.0 valid reply.
+// reply ::= H x02 x00 R value
+func (this *Encoder2) EncodeReply(v Any) error {
+	var l = len(this.buffer)
+
+	this.buffer = append(this.buffer, BC_VERSION, 2, 0, BC_REPLY)
+	if err := this.encode(v); err != nil {
+		this.buffer = this.buffer[:l]
+		return err
+	}
+
+	return nil
+}
+
+// EncodeFault encodes a hessian 2.0 fault reply of @err.
+// fault ::= H x02 x00 F H (value value)* Z
+// @err is converted to a fault in the same way as hessian.EncodeFault.
+func (this *Encoder2) EncodeFault(err error) error {
+	var (
+		l     = len(this.buffer)
+		fault = newFault(err)
+	)
+
+	this.buffer = append(this.buffer, BC_VERSION, 2, 0, BC_FAULT, BC_MAP_UNTYPED)
+	this.encString("code")
+	this.encString(fault.Code)
+	this.encString("message")
+	this.encString(fault.Message)
+	if fault.Detail != nil {
+		this.encString("detail")
+		if e := this.encode(fault.Detail); e != nil {
+			this.buffer = this.buffer[:l]
+			return e
+		}
+	}
+	this.buffer = append(this.buffer, BC_END)
+
+	return nil
+}
@@ -0,0 +1,258 @@
+/******************************************************
+# DESC    : encode2.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 11:05
+# FILE    : encode2_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -v -run TestEncoder2
+
+// the examples of http://hessian.caucho.com/doc/hessian-serialization.html
+func TestEncoder2Primitive(t *testing.T) {
+	var cases = []struct {
+		v    Any
+		want []byte
+	}{
+		{nil, []byte{'N'}},
+		{true, []byte{'T'}},
+		{false, []byte{'F'}},
+		{int32(0), []byte{0x90}},
+		{int32(-16), []byte{0x80}},
+		{int32(47), []byte{0xbf}},
+		{int32(-2048), []byte{0xc0, 0x00}},
+		{int32(-256), []byte{0xc7, 0x00}},
+		{int32(2047), []byte{0xcf, 0xff}},
+		{int32(-262144), []byte{0xd0, 0x00, 0x00}},
+		{int32(262143), []byte{0xd7, 0xff, 0xff}},
+		{int32(262144), []byte{'I', 0x00, 0x04, 0x00, 0x00}},
+		{int64(0), []byte{0xe0}},
+		{int64(-8), []byte{0xd8}},
+		{int64(15), []byte{0xef}},
+		{int64(-2048), []byte{0xf0, 0x00}},
+		{int64(2047), []byte{0xff, 0xff}},
+		{int64(-262144), []byte{0x38, 0x00, 0x00}},
+		{int64(262143), []byte{0x3f, 0xff, 0xff}},
+		{int64(262144), []byte{0x59, 0x00, 0x04, 0x00, 0x00}},
+		{int64(1) << 32, []byte{'L', 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+		{300, []byte{0xf9, 0x2c}},
+		{0.0, []byte{0x5b}},
+		{1.0, []byte{0x5c}},
+		{-128.0, []byte{0x5d, 0x80}},
+		{127.0, []byte{0x5d, 0x7f}},
+		{-32768.0, []byte{0x5e, 0x80, 0x00}},
+		{32767.0, []byte{0x5e, 0x7f, 0xff}},
+		{12.25, []byte{0x5f, 0x00, 0x00, 0x2f, 0xda}},
+		{1e10 + 0.5, append([]byte{'D'}, PackFloat64(1e10+0.5)...)},
+		{time.Date(1998, 5, 8, 9, 51, 31, 0, time.UTC), []byte{0x4a, 0x00, 0x00, 0x00, 0xd0, 0x4b, 0x92, 0x84, 0xb8}},
+		{time.Date(1998, 5, 8, 9, 51, 0, 0, time.UTC), []byte{0x4b, 0x00, 0xe3, 0x83, 0x8f}},
+		{"", []byte{0x00}},
+		{"hello", []byte{0x05, 'h', 'e', 'l', 'l', 'o'}},
+		{"Ã", []byte{0x01, 0xc3, 0x83}},
+		// a char out of the BMP is two java chars
+		{"\U0001F600", []byte{0x02, 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}},
+		{[]byte{}, []byte{0x20}},
+		{[]byte{1, 2, 3}, []byte{0x23, 1, 2, 3}},
+		{[]Any{int32(0), int32(1)}, []byte{0x7a, 0x90, 0x91}},
+		{map[Any]Any{int32(1): "fee"}, []byte{'H', 0x91, 0x03, 'f', 'e', 'e', 'Z'}},
+		{map[int32]string{1: "fee"}, []byte{'H', 0x91, 0x03, 'f', 'e', 'e', 'Z'}},
+		{map[string]int32{}, []byte{'H', 'Z'}},
+		// int[] is a typed list
+		{[]int32{0, 1}, []byte{0x72, 0x04, '[', 'i', 'n', 't', 0x90, 0x91}},
+		{[]map[Any]Any{}, []byte{0x78}},
+	}
+
+	for _, c := range cases {
+		var e = NewEncoder2()
+		if err := e.Encode(c.v); err != nil {
+			t.Errorf("Encode(%#v) = error:%v", c.v, err)
+			continue
+		}
+		if !bytes.Equal(e.Buffer(), c.want) {
+			t.Errorf("Encode(%#v) = %s, want %s", c.v, SprintHex(e.Buffer()), SprintHex(c.want))
+		}
+	}
+}
+
+func TestEncoder2Chunk(t *testing.T) {
+	var (
+		e = NewEncoder2()
+		s = strings.Repeat("a", CHUNK_SIZE+1)
+		b = bytes.Repeat([]byte{1}, CHUNK_SIZE+1024)
+	)
+
+	e.Encode(s)
+	if buf := e.Buffer(); buf[0] != 'R' || UnpackUint16(buf[1:3]) != CHUNK_SIZE || buf[3+CHUNK_SIZE] != 0x01 || len(buf) != CHUNK_SIZE+5 {
+		t.Errorf("Encode(string of %d chars) = %v", len(s), buf[:3])
+	}
+
+	e.Reset()
+	e.Encode(b)
+	if buf := e.Buffer(); buf[0] != 'A' || buf[3+CHUNK_SIZE] != 'B' || UnpackUint16(buf[4+CHUNK_SIZE:]) != 1024 {
+		t.Errorf("Encode(binary of %d bytes) = %v", len(b), buf[:3])
+	}
+}
+
+type Car struct {
+	color string
+	model string
+}
+
+func (c Car) GetType() string {
+	return "example.Car"
+}
+
+func (c Car) GetColor() string {
+	return c.color
+}
+
+func (c Car) GetModel() string {
+	return c.model
+}
+
+func TestEncoder2Object(t *testing.T) {
+	var (
+		e    = NewEncoder2()
+		want []byte
+	)
+
+	e.Encode(Car{color: "red", model: "corvette"})
+	e.Encode(&Car{color: "green", model: "civic"})
+	want = append(want, 'C', 0x0b)
+	want = append(want, "example.Car"...)
+	want = append(want, 0x92, 0x05)
+	want = append(want, "color"...)
+	want = append(want, 0x05)
+	want = append(want, "model"...)
+	want = append(want, 0x60, 0x03)
+	want = append(want, "red"...)
+	want = append(want, 0x08)
+	want = append(want, "corvette"...)
+	// the class definition is written only once
+	want = append(want, 0x60, 0x05)
+	want = append(want, "green"...)
+	want = append(want, 0x05)
+	want = append(want, "civic"...)
+	if !bytes.Equal(e.Buffer(), want) {
+		t.Errorf("Encode(Car) = %s, want %s", SprintHex(e.Buffer()), SprintHex(want))
+	}
+
+	if err := e.Encode(make(chan int)); err == nil || !bytes.Equal(e.Buffer(), want) {
+		t.Errorf("Encode(chan) = error:%v, buffer:%v", err, e.Buffer())
+	}
+}
+
+func TestEncoder2Reply(t *testing.T) {
+	var e = NewEncoder2()
+
+	e.EncodeReply("hello")
+	want := []byte{'H', 2, 0, 'R', 0x05, 'h', 'e', 'l', 'l', 'o'}
+	if !bytes.Equal(e.Buffer(), want) {
+		t.Errorf("EncodeReply(hello) = %v, want %v", e.Buffer(), want)
+	}
+
+	e.Reset()
+	e.EncodeFault(&Fault{Code: FAULT_NO_SUCH_METHOD, Message: "no"})
+	want = []byte{'H', 2, 0, 'F', 'H', 0x04, 'c', 'o', 'd', 'e', 0x15}
+	want = append(want, FAULT_NO_SUCH_METHOD...)
+	want = append(want, 0x07)
+	want = append(want, "message"...)
+	want = append(want, 0x02, 'n', 'o', 'Z')
+	if !bytes.Equal(e.Buffer(), want) {
+		t.Errorf("EncodeFault(NoSuchMethodException) = %v, want %v", e.Buffer(), want)
+	}
+
+	e.Reset()
+	e.EncodeFault(fmt.Errorf("divide by zero"))
+	if b := e.Buffer(); !bytes.Contains(b, []byte(JAVA_SERVICE_EXCEPTION)) || b[len(b)-1] != 'Z' {
+		t.Errorf("EncodeFault(error) = %v", b)
+	}
+}
+
+func TestEncoder2Deterministic(t *testing.T) {
+	var (
+		e    = NewEncoder2()
+		want = []byte{BC_MAP_UNTYPED}
+		v    = []Any{
+			map[Any]Any{"c": int32(2), "b": int32(1), "a": int32(0), "d": []Any{}},
+			map[string]Any{"y": map[Any]Any{int64(2): true, int64(1): false}, "x": NewSet("b", "a", "c")},
+		}
+	)
+
+	e.SetDeterministic(true)
+	e.Encode(v[0])
+	for i, k := range []string{"a", "b", "c"} {
+		want = append(want, 0x01, k[0], BC_INT_ZERO+byte(i))
+	}
+	want = append(want, 0x01, 'd', BC_LIST_DIRECT_UNTYPED, BC_END)
+	if !bytes.Equal(e.Buffer(), want) {
+		t.Errorf("Encode(%v) = %v, want %v", v[0], e.Buffer(), want)
+	}
+
+	e.Reset()
+	e.Encode(v)
+	b := append([]byte(nil), e.Buffer()...)
+	for i := 0; i < 8; i++ {
+		e.Reset()
+		e.Encode(v)
+		if !bytes.Equal(e.Buffer(), b) {
+			t.Fatalf("Encode(%v) = %v, want %v", v, e.Buffer(), b)
+		}
+	}
+}
+
+func TestEncoder2EmptyMap(t *testing.T) {
+	var e = NewEncoder2()
+
+	e.Encode("a")
+	e.Encode(map[Any]Any{})
+	e.Encode(map[string]int32{})
+	e.Encode(TreeMap{})
+	e.Encode(int32(1))
+	d := NewDecoder2(e.Buffer())
+	for _, want := range []Any{"a", map[Any]Any{}, map[Any]Any{}, TreeMap{}, int32(1)} {
+		if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, want) {
+			t.Errorf("Decode() = %#v, error:%v, want %#v", v, err, want)
+		}
+	}
+}
+
+// go test -bench Encoder2POJO -benchmem
+
+func BenchmarkEncoder2POJO(b *testing.B) {
+	var v = Account{Name: "alex", Balance: big.NewFloat(1.5)}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e := NewEncoder2()
+		e.Encode(v)
+	}
+}
+
+// BenchmarkEncoder2POJOUncached is the baseline of BenchmarkEncoder2POJO,
+// whose methods of the POJO are walked every time without the cached plan.
+func BenchmarkEncoder2POJOUncached(b *testing.B) {
+	var (
+		v   = Account{Name: "alex", Balance: big.NewFloat(1.5)}
+		typ = reflect.TypeOf(v)
+	)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pojoPlans.Delete(typ)
+		e := NewEncoder2()
+		e.Encode(v)
+	}
+}
@@ -10,6 +10,9 @@ package hessian
 
 import (
 	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -128,3 +131,169 @@ func TestEncMap(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestEncodeReply(t *testing.T) {
+	var b []byte
+	b = EncodeReply(int32(100), b)
+	want := []byte{'r', 1, 0, 'I', 0, 0, 0, 100, 'z'}
+	assert(want, b, t)
+
+	v, err := NewDecoder(b).Decode()
+	if err != nil || v != int32(100) {
+		t.Fatalf("Decode(EncodeReply(100)) = res:%v, err:%v", v, err)
+	}
+}
+
+func TestEncodeFault(t *testing.T) {
+	var b []byte
+	b = EncodeFault(&Fault{Code: FAULT_NO_SUCH_METHOD, Message: "no method"}, b)
+	_, err := NewDecoder(b).Decode()
+	if fault, ok := err.(*Fault); !ok || fault.Code != FAULT_NO_SUCH_METHOD || fault.Message != "no method" || fault.Detail != nil {
+		t.Fatalf("Decode(EncodeFault(NoSuchMethodException)) = err:%#v", err)
+	}
+
+	// go error is encoded as a HessianServiceException
+	b = EncodeFault(fmt.Errorf("divide by zero"), b[:0])
+	_, err = NewDecoder(b).Decode()
+	fault, ok := err.(*Fault)
+	if !ok || fault.Code != FAULT_SERVICE || fault.Message != "divide by zero" {
+		t.Fatalf("Decode(EncodeFault(error)) = err:%#v", err)
+	}
+	detail, ok := fault.Detail.(map[Any]Any)
+	if !ok || detail["detailMessage"] != "divide by zero" || detail["code"] != FAULT_SERVICE {
+		t.Fatalf("Decode(EncodeFault(error)) = detail:%#v", fault.Detail)
+	}
+	if !bytes.Contains(b, []byte(JAVA_SERVICE_EXCEPTION)) {
+		t.Fatalf("EncodeFault(error) = %v, java exception type not found", b)
+	}
+
+	// the replies are decoded one by one from a stream
+	b = EncodeFault(&Fault{Code: FAULT_NO_SUCH_METHOD, Message: "no method"}, b[:0])
+	b = EncodeReply(int32(100), b)
+	b = Encode("next", b)
+	d := NewDecoder(b)
+	if _, err = d.Decode(); err == nil {
+		t.Fatalf("Decode(fault) = err:nil")
+	}
+	for _, want := range []Any{int32(100), "next"} {
+		if v, err := d.Decode(); err != nil || v != want {
+			t.Errorf("Decode() after the fault = res:%#v, err:%v, want %#v", v, err, want)
+		}
+	}
+}
+
+func TestEncDeterministic(t *testing.T) {
+	var (
+		e    = NewEncoder()
+		want = []byte{'M'}
+	)
+
+	e.SetDeterministic(true)
+	for i, k := range []string{"a", "b", "c", "d", "e", "f"} {
+		want = encInt32(int32(i), encString(k, want))
+	}
+	want = append(want, 'z')
+
+	var values = []Any{
+		map[Any]Any{"f": int32(5), "e": int32(4), "d": int32(3), "c": int32(2), "b": int32(1), "a": int32(0)},
+		map[string]int32{"f": 5, "e": 4, "d": 3, "c": 2, "b": 1, "a": 0},
+	}
+	for _, v := range values {
+		for i := 0; i < 8; i++ {
+			if b := e.Encode(v, nil); !bytes.Equal(b, want) {
+				t.Fatalf("Encode(%v) = %v, want %v", v, b, want)
+			}
+		}
+	}
+
+	// the nested maps and the set items are sorted too
+	var v = []Any{
+		TreeMap{"y": map[Any]Any{int32(2): "2", int32(1): "1", "z": nil}, "x": nil},
+		NewSet("c", "b", "a", int32(3), int64(1)),
+	}
+	b := e.Encode(v, nil)
+	for i := 0; i < 8; i++ {
+		if !bytes.Equal(e.Encode(v, nil), b) {
+			t.Fatalf("Encode(%v) = %v, want %v", v, e.Encode(v, nil), b)
+		}
+	}
+}
+
+func TestEncEmptyMap(t *testing.T) {
+	var cases = []struct {
+		v    Any
+		want []byte
+	}{
+		{map[Any]Any{}, []byte{'M', 'z'}},
+		{map[string]int32{}, []byte{'M', 'z'}},
+		{map[string]int32(nil), []byte{'N'}},
+		{TreeMap{}, append(append([]byte{'M'}, encType(JAVA_TREE_MAP, nil)...), 'z')},
+	}
+	for _, c := range cases {
+		if b := Encode(c.v, nil); !bytes.Equal(b, c.want) {
+			t.Errorf("Encode(%#v) = %v, want %v", c.v, b, c.want)
+		}
+	}
+
+	// the empty map does not shift the following arguments
+	b := Encode("a", nil)
+	b = Encode(map[string]Any{}, b)
+	b = Encode(TreeMap{}, b)
+	b = Encode(int32(1), b)
+	d := NewDecoder(b)
+	for _, want := range []Any{"a", map[Any]Any{}, TreeMap{}, int32(1)} {
+		if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, want) {
+			t.Errorf("Decode() = %#v, error:%v, want %#v", v, err, want)
+		}
+	}
+}
+
+func TestEncShortKeyMap(t *testing.T) {
+	var want = map[Any]Any{int32(1): "a", int32(-2): "b"}
+	for _, m := range []Any{
+		map[int16]string{1: "a", -2: "b"},
+		map[JavaShort]string{1: "a", -2: "b"},
+	} {
+		if v, err := NewDecoder(Encode(m, nil)).Decode(); err != nil || !reflect.DeepEqual(v, want) {
+			t.Errorf("Decode(Encode(%#v)) = %#v, error:%v, want %#v", m, v, err, want)
+		}
+
+		e := NewEncoder2()
+		if err := e.Encode(m); err != nil {
+			t.Fatalf("Encoder2.Encode(%#v) = error:%v", m, err)
+		}
+		if v, err := NewDecoder2(e.Buffer()).Decode(); err != nil || !reflect.DeepEqual(v, want) {
+			t.Errorf("Decoder2.Decode(Encode(%#v)) = %#v, error:%v, want %#v", m, v, err, want)
+		}
+	}
+}
+
+// go test -bench EncodePOJO -benchmem
+
+func BenchmarkEncodePOJO(b *testing.B) {
+	var (
+		buf []byte
+		v   = Account{Name: "alex", Balance: big.NewFloat(1.5)}
+	)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = Encode(v, buf[:0])
+	}
+}
+
+// BenchmarkEncodePOJOUncached is the baseline of BenchmarkEncodePOJO, whose
+// methods of the POJO are walked every time without the cached plan.
+func BenchmarkEncodePOJOUncached(b *testing.B) {
+	var (
+		buf []byte
+		v   = Account{Name: "alex", Balance: big.NewFloat(1.5)}
+		typ = reflect.TypeOf(v)
+	)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pojoPlans.Delete(typ)
+		buf = Encode(v, buf[:0])
+	}
+}
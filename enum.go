@@ -0,0 +1,153 @@
+/******************************************************
+# DESC    : java enum registry
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 21:15
+# FILE    : enum.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+const (
+	JAVA_ENUM_NAME_FIELD = "name"
+)
+
+var (
+	enumReg = EnumRegistry{
+		javaTypes: make(map[string]*javaEnum),
+		goTypes:   make(map[reflect.Type]*javaEnum),
+	}
+)
+
+// javaEnum is the constants of a java enum and their go values.
+type javaEnum struct {
+	javaType string
+	goType   reflect.Type
+	names    map[Any]string // go value -> java name
+	values   map[string]Any // java name -> go value
+}
+
+// EnumRegistry maps the java enum classes to go types.
+type EnumRegistry struct {
+	sync.RWMutex
+	javaTypes map[string]*javaEnum
+	goTypes   map[reflect.Type]*javaEnum
+}
+
+// RegisterJavaEnum maps java enum class @javaType to the go type of the keys
+// of @names, which are the go constants of the java enum constants, such as
+//
+//	type Color int32
+//	const (
+//		RED Color = iota
+//		GREEN
+//	)
+//	RegisterJavaEnum("com.foo.Color", map[Any]string{RED: "RED", GREEN: "GREEN"})
+//
+// The kind of the go type should be integer or string. The go value is
+// encoded as an object of @javaType whose only field "name" is the java name,
+// which is what hessian does for java enum, and the object is decoded as the
+// go value. The value of string kind not in @names is encoded and decoded as
+// the name itself.
+func RegisterJavaEnum(javaType string, names map[Any]string) error {
+	var e = &javaEnum{
+		javaType: javaType,
+		names:    make(map[Any]string, len(names)),
+		values:   make(map[string]Any, len(names)),
+	}
+
+	for v, name := range names {
+		var typ = reflect.TypeOf(v)
+		if e.goType == nil {
+			e.goType = typ
+		}
+		if typ != e.goType {
+			return fmt.Errorf("java enum %s should be one go type, but it is %s and %s", javaType, e.goType, typ)
+		}
+		if _, ok := e.values[name]; ok {
+			return fmt.Errorf("java enum %s has duplicate name %s", javaType, name)
+		}
+		e.names[v] = name
+		e.values[name] = v
+	}
+	if e.goType == nil {
+		return fmt.Errorf("java enum %s has no constant", javaType)
+	}
+	switch e.goType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.String:
+	default:
+		return fmt.Errorf("java enum %s should be integer or string, but it is %s", javaType, e.goType)
+	}
+
+	enumReg.Lock()
+	enumReg.javaTypes[javaType] = e
+	enumReg.goTypes[e.goType] = e
+	enumReg.Unlock()
+
+	return nil
+}
+
+// getJavaEnum returns the java enum of java class @javaType.
+func getJavaEnum(javaType string) (*javaEnum, bool) {
+	enumReg.RLock()
+	e, ok := enumReg.javaTypes[javaType]
+	enumReg.RUnlock()
+
+	return e, ok
+}
+
+// getJavaEnumOf returns the java enum of go type @typ.
+func getJavaEnumOf(typ reflect.Type) (*javaEnum, bool) {
+	enumReg.RLock()
+	e, ok := enumReg.goTypes[typ]
+	enumReg.RUnlock()
+
+	return e, ok
+}
+
+// name returns the java name of go value @v.
+func (e *javaEnum) name(v Any) (string, error) {
+	if name, ok := e.names[v]; ok {
+		return name, nil
+	}
+	if e.goType.Kind() == reflect.String {
+		return reflect.ValueOf(v).String(), nil
+	}
+
+	return "", fmt.Errorf("%v is not a constant of java enum %s", v, e.javaType)
+}
+
+// value returns the go value of java name @name.
+func (e *javaEnum) value(name Any) (Any, error) {
+	var s, ok = name.(string)
+	if !ok {
+		return nil, fmt.Errorf("the name of java enum %s should be string, but it is %T", e.javaType, name)
+	}
+	if v, ok := e.values[s]; ok {
+		return v, nil
+	}
+	if e.goType.Kind() == reflect.String {
+		return reflect.ValueOf(s).Convert(e.goType).Interface(), nil
+	}
+
+	return nil, fmt.Errorf("%s is not a constant of java enum %s", s, e.javaType)
+}
+
+// toEnum converts the decoded object or map @v of java class @javaType to
+// the go value of its field "name" if @javaType is a registered java enum.
+func toEnum(v map[Any]Any, javaType string) (Any, bool, error) {
+	var e, ok = getJavaEnum(javaType)
+	if !ok {
+		return nil, false, nil
+	}
+
+	res, err := e.value(v[JAVA_ENUM_NAME_FIELD])
+	return res, true, err
+}
@@ -0,0 +1,100 @@
+/******************************************************
+# DESC    : enum.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 21:15
+# FILE    : enum_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// go test -v -run TestJavaEnum
+
+type Color int32
+
+const (
+	RED Color = iota
+	GREEN
+	BLUE
+)
+
+type Level string
+
+const (
+	LEVEL_INFO  Level = "INFO"
+	LEVEL_ERROR Level = "ERROR"
+)
+
+func init() {
+	RegisterJavaEnum("com.foo.Color", map[Any]string{RED: "RED", GREEN: "GREEN", BLUE: "BLUE"})
+	RegisterJavaEnum("com.foo.Level", map[Any]string{LEVEL_INFO: "INFO", LEVEL_ERROR: "ERROR"})
+}
+
+func TestJavaEnum(t *testing.T) {
+	b := Encode(GREEN, nil)
+	want := append([]byte{'M'}, encType("com.foo.Color", nil)...)
+	want = append(encString("GREEN", encString("name", want)), 'z')
+	if !bytes.Equal(b, want) {
+		t.Errorf("Encode(GREEN) = %s, want %s", SprintHex(b), SprintHex(want))
+	}
+	if v, err := NewDecoder(b).Decode(); err != nil || v != GREEN {
+		t.Errorf("Decode(GREEN) = %#v, error:%v", v, err)
+	}
+
+	var (
+		e    = NewEncoder2()
+		list = []Any{RED, BLUE, LEVEL_ERROR, Level("WARN"), RED}
+	)
+	if err := e.Encode(list); err != nil {
+		t.Fatalf("Encode(%v) = error:%v", list, err)
+	}
+	if n := bytes.Count(e.Buffer(), []byte("com.foo.Color")); n != 1 {
+		t.Errorf("Encode(%v) = %s", list, SprintHex(e.Buffer()))
+	}
+	v, err := NewDecoder2(e.Buffer()).Decode()
+	if err != nil || !reflect.DeepEqual(v, list) {
+		t.Errorf("Decode(%v) = %#v, error:%v", list, v, err)
+	}
+
+	// the reference of enum
+	e.Reset()
+	e.buffer = append(e.buffer, BC_LIST_DIRECT_UNTYPED+2)
+	e.Encode(BLUE)
+	e.buffer = append(e.buffer, BC_REF, 0x91)
+	if v, err = NewDecoder2(e.Buffer()).Decode(); err != nil || !reflect.DeepEqual(v, []Any{BLUE, BLUE}) {
+		t.Errorf("Decode(list of enum) = %#v, error:%v", v, err)
+	}
+
+	if b = Encode(Color(10), nil); b != nil {
+		t.Errorf("Encode(Color(10)) = %s", SprintHex(b))
+	}
+	if err = e.Encode(Color(10)); err == nil {
+		t.Errorf("Encoder2.Encode(Color(10)) should fail")
+	}
+	b = append([]byte{'M'}, encType("com.foo.Color", nil)...)
+	b = append(encString("WHITE", encString("name", b)), 'z')
+	if v, err = NewDecoder(b).Decode(); err == nil {
+		t.Errorf("Decode(WHITE) = %#v", v)
+	}
+}
+
+func TestRegisterJavaEnum(t *testing.T) {
+	var cases = []map[Any]string{
+		nil,
+		{RED: "RED", LEVEL_INFO: "INFO"},
+		{RED: "RED", GREEN: "RED"},
+		{1.0: "ONE"},
+	}
+
+	for _, names := range cases {
+		if err := RegisterJavaEnum("com.foo.Bad", names); err == nil {
+			t.Errorf("RegisterJavaEnum(%v) should fail", names)
+		}
+	}
+}
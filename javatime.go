@@ -0,0 +1,442 @@
+/******************************************************
+# DESC    : java.time and java.sql date classes
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 20:30
+# FILE    : javatime.go
+******************************************************/
+
+// refers to com.alibaba.com.caucho.hessian.io.java8 of dubbo hessian-lite,
+// which serializes the java.time classes by their HessianHandle classes,
+// and com.caucho.hessian.io.SqlDateSerializer.
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	JAVA8_HANDLE_PACKAGE = "com.alibaba.com.caucho.hessian.io.java8."
+
+	JAVA_LOCAL_DATE      = JAVA8_HANDLE_PACKAGE + "LocalDateHandle"
+	JAVA_LOCAL_TIME      = JAVA8_HANDLE_PACKAGE + "LocalTimeHandle"
+	JAVA_LOCAL_DATE_TIME = JAVA8_HANDLE_PACKAGE + "LocalDateTimeHandle"
+	JAVA_ZONE_OFFSET     = JAVA8_HANDLE_PACKAGE + "ZoneOffsetHandle"
+	JAVA_ZONED_DATE_TIME = JAVA8_HANDLE_PACKAGE + "ZonedDateTimeHandle"
+	JAVA_INSTANT         = JAVA8_HANDLE_PACKAGE + "InstantHandle"
+	JAVA_DURATION        = JAVA8_HANDLE_PACKAGE + "DurationHandle"
+	JAVA_SQL_TIMESTAMP   = "java.sql.Timestamp"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// the java classes of the handle classes, which are used in the jvm type
+	// descriptors of dubbo invocation parameters
+	javaHandleClasses = map[string]string{
+		JAVA_LOCAL_DATE:      "java.time.LocalDate",
+		JAVA_LOCAL_TIME:      "java.time.LocalTime",
+		JAVA_LOCAL_DATE_TIME: "java.time.LocalDateTime",
+		JAVA_ZONE_OFFSET:     "java.time.ZoneOffset",
+		JAVA_ZONED_DATE_TIME: "java.time.ZonedDateTime",
+		JAVA_INSTANT:         "java.time.Instant",
+		JAVA_DURATION:        "java.time.Duration",
+	}
+)
+
+func init() {
+	// the java.time classes serialized by their fields, whose names are the
+	// same as the ones of the handle classes
+	registerBuiltinPOJO(LocalDate{}, "java.time.LocalDate")
+	registerBuiltinPOJO(LocalTime{}, "java.time.LocalTime")
+	registerBuiltinPOJO(LocalDateTime{}, "java.time.LocalDateTime")
+	registerBuiltinPOJO(ZoneOffset{}, "java.time.ZoneOffset")
+	registerBuiltinPOJO(ZonedDateTime{})
+	registerBuiltinPOJO(Instant{}, "java.time.Instant")
+	registerBuiltinPOJO(Duration{}, "java.time.Duration")
+	registerBuiltinPOJO(Timestamp{})
+}
+
+// LocalDate is a java.time.LocalDate, a date without time zone.
+type LocalDate struct {
+	Year  int32
+	Month int32
+	Day   int32
+}
+
+func NewLocalDate(t time.Time) LocalDate {
+	return LocalDate{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+}
+
+func (LocalDate) GetType() string {
+	return JAVA_LOCAL_DATE
+}
+
+func (d LocalDate) GetYear() int32 {
+	return d.Year
+}
+
+func (d LocalDate) GetMonth() int32 {
+	return d.Month
+}
+
+func (d LocalDate) GetDay() int32 {
+	return d.Day
+}
+
+func (d *LocalDate) SetYear(year int32) {
+	d.Year = year
+}
+
+func (d *LocalDate) SetMonth(month int32) {
+	d.Month = month
+}
+
+func (d *LocalDate) SetDay(day int32) {
+	d.Day = day
+}
+
+// Time returns the midnight of the date in local time zone.
+func (d LocalDate) Time() time.Time {
+	return time.Date(int(d.Year), time.Month(d.Month), int(d.Day), 0, 0, 0, 0, time.Local)
+}
+
+func (d LocalDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// LocalTime is a java.time.LocalTime, a time of day without time zone.
+type LocalTime struct {
+	Hour   int32
+	Minute int32
+	Second int32
+	Nano   int32
+}
+
+func NewLocalTime(t time.Time) LocalTime {
+	return LocalTime{
+		Hour:   int32(t.Hour()),
+		Minute: int32(t.Minute()),
+		Second: int32(t.Second()),
+		Nano:   int32(t.Nanosecond()),
+	}
+}
+
+func (LocalTime) GetType() string {
+	return JAVA_LOCAL_TIME
+}
+
+func (t LocalTime) GetHour() int32 {
+	return t.Hour
+}
+
+func (t LocalTime) GetMinute() int32 {
+	return t.Minute
+}
+
+func (t LocalTime) GetSecond() int32 {
+	return t.Second
+}
+
+func (t LocalTime) GetNano() int32 {
+	return t.Nano
+}
+
+func (t *LocalTime) SetHour(hour int32) {
+	t.Hour = hour
+}
+
+func (t *LocalTime) SetMinute(minute int32) {
+	t.Minute = minute
+}
+
+func (t *LocalTime) SetSecond(second int32) {
+	t.Second = second
+}
+
+func (t *LocalTime) SetNano(nano int32) {
+	t.Nano = nano
+}
+
+func (t LocalTime) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d.%09d", t.Hour, t.Minute, t.Second, t.Nano)
+}
+
+// LocalDateTime is a java.time.LocalDateTime, a date-time without time zone.
+type LocalDateTime struct {
+	Date LocalDate
+	Time LocalTime
+}
+
+func NewLocalDateTime(t time.Time) LocalDateTime {
+	return LocalDateTime{Date: NewLocalDate(t), Time: NewLocalTime(t)}
+}
+
+func (LocalDateTime) GetType() string {
+	return JAVA_LOCAL_DATE_TIME
+}
+
+func (t LocalDateTime) GetDate() LocalDate {
+	return t.Date
+}
+
+func (t LocalDateTime) GetTime() LocalTime {
+	return t.Time
+}
+
+func (t *LocalDateTime) SetDate(date LocalDate) {
+	t.Date = date
+}
+
+func (t *LocalDateTime) SetTime(lt LocalTime) {
+	t.Time = lt
+}
+
+// In returns the date-time in time zone @loc.
+func (t LocalDateTime) In(loc *time.Location) time.Time {
+	return time.Date(int(t.Date.Year), time.Month(t.Date.Month), int(t.Date.Day),
+		int(t.Time.Hour), int(t.Time.Minute), int(t.Time.Second), int(t.Time.Nano), loc)
+}
+
+func (t LocalDateTime) String() string {
+	return t.Date.String() + "T" + t.Time.String()
+}
+
+// ZoneOffset is a java.time.ZoneOffset, the offset from UTC in seconds.
+type ZoneOffset struct {
+	Seconds int32
+}
+
+func (ZoneOffset) GetType() string {
+	return JAVA_ZONE_OFFSET
+}
+
+func (o ZoneOffset) GetSeconds() int32 {
+	return o.Seconds
+}
+
+func (o *ZoneOffset) SetSeconds(seconds int32) {
+	o.Seconds = seconds
+}
+
+// SetTotalSeconds sets the field totalSeconds of java.time.ZoneOffset.
+func (o *ZoneOffset) SetTotalSeconds(seconds int32) {
+	o.Seconds = seconds
+}
+
+// ID returns the java zone id of the offset, such as "Z" and "+08:00".
+func (o ZoneOffset) ID() string {
+	var (
+		sign = '+'
+		s    = o.Seconds
+	)
+
+	if s == 0 {
+		return "Z"
+	}
+	if s < 0 {
+		sign, s = '-', -s
+	}
+	if s%60 != 0 {
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, s/3600, s/60%60)
+}
+
+// ZonedDateTime is a java.time.ZonedDateTime, a date-time with a time zone
+// such as "Asia/Shanghai", or "+08:00" if it has only an offset.
+type ZonedDateTime struct {
+	DateTime LocalDateTime
+	Offset   ZoneOffset
+	ZoneId   string
+}
+
+// NewZonedDateTime converts @t to ZonedDateTime. The time zone is the name
+// of the location of @t if it is a IANA time zone, otherwise the offset.
+func NewZonedDateTime(t time.Time) ZonedDateTime {
+	var (
+		_, offset = t.Zone()
+		name      = t.Location().String()
+		z         = ZonedDateTime{DateTime: NewLocalDateTime(t), Offset: ZoneOffset{Seconds: int32(offset)}}
+	)
+
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" || name == "" {
+		name = z.Offset.ID()
+	}
+	z.ZoneId = name
+
+	return z
+}
+
+func (ZonedDateTime) GetType() string {
+	return JAVA_ZONED_DATE_TIME
+}
+
+func (z ZonedDateTime) GetDateTime() LocalDateTime {
+	return z.DateTime
+}
+
+func (z ZonedDateTime) GetOffset() ZoneOffset {
+	return z.Offset
+}
+
+func (z ZonedDateTime) GetZoneId() string {
+	return z.ZoneId
+}
+
+func (z *ZonedDateTime) SetDateTime(dateTime LocalDateTime) {
+	z.DateTime = dateTime
+}
+
+func (z *ZonedDateTime) SetOffset(offset ZoneOffset) {
+	z.Offset = offset
+}
+
+func (z *ZonedDateTime) SetZoneId(zoneId string) {
+	z.ZoneId = zoneId
+}
+
+// Time returns the time in the location of ZoneId, or in the fixed zone of
+// Offset if ZoneId is not a IANA time zone.
+func (z ZonedDateTime) Time() time.Time {
+	var t = z.DateTime.In(time.FixedZone(z.Offset.ID(), int(z.Offset.Seconds)))
+	if z.ZoneId == "" || z.ZoneId == z.Offset.ID() {
+		return t
+	}
+	if loc, err := time.LoadLocation(z.ZoneId); err == nil {
+		return t.In(loc)
+	}
+
+	return t
+}
+
+func (z ZonedDateTime) String() string {
+	return z.DateTime.String() + z.Offset.ID() + "[" + z.ZoneId + "]"
+}
+
+// Instant is a java.time.Instant, the seconds and nanoseconds since the epoch.
+type Instant struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func NewInstant(t time.Time) Instant {
+	return Instant{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+func (Instant) GetType() string {
+	return JAVA_INSTANT
+}
+
+func (i Instant) GetSeconds() int64 {
+	return i.Seconds
+}
+
+func (i Instant) GetNanos() int32 {
+	return i.Nanos
+}
+
+func (i *Instant) SetSeconds(seconds int64) {
+	i.Seconds = seconds
+}
+
+func (i *Instant) SetNanos(nanos int32) {
+	i.Nanos = nanos
+}
+
+func (i Instant) Time() time.Time {
+	return time.Unix(i.Seconds, int64(i.Nanos))
+}
+
+// Duration is a java.time.Duration, whose Nanos is in [0, 999999999] even
+// if it is negative. time.Duration is encoded as a Duration.
+type Duration struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func NewDuration(d time.Duration) Duration {
+	var (
+		s = int64(d / time.Second)
+		n = int32(d % time.Second)
+	)
+
+	if n < 0 {
+		s, n = s-1, n+int32(time.Second)
+	}
+
+	return Duration{Seconds: s, Nanos: n}
+}
+
+func (Duration) GetType() string {
+	return JAVA_DURATION
+}
+
+func (d Duration) GetSeconds() int64 {
+	return d.Seconds
+}
+
+func (d Duration) GetNanos() int32 {
+	return d.Nanos
+}
+
+func (d *Duration) SetSeconds(seconds int64) {
+	d.Seconds = seconds
+}
+
+func (d *Duration) SetNanos(nanos int32) {
+	d.Nanos = nanos
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d.Seconds)*time.Second + time.Duration(d.Nanos)
+}
+
+// Timestamp is a java.sql.Timestamp, which is serialized as an object whose
+// only field "value" is a date in milliseconds.
+type Timestamp struct {
+	Value time.Time
+}
+
+func (Timestamp) GetType() string {
+	return JAVA_SQL_TIMESTAMP
+}
+
+func (t Timestamp) GetValue() time.Time {
+	return t.Value
+}
+
+func (t *Timestamp) SetValue(value time.Time) {
+	t.Value = value
+}
+
+func (t Timestamp) Time() time.Time {
+	return t.Value
+}
+
+// convertTime converts the decoded java date or duration @v to time.Time or
+// time.Duration of type @typ. The return value ok is false if @v can not be
+// converted to @typ.
+func convertTime(v Any, typ reflect.Type) (reflect.Value, bool) {
+	switch typ {
+	case timeType:
+		switch t := v.(type) {
+		case interface{ Time() time.Time }:
+			return reflect.ValueOf(t.Time()), true
+		case *LocalDateTime:
+			return reflect.ValueOf(t.In(time.Local)), true
+		case LocalDateTime:
+			return reflect.ValueOf(t.In(time.Local)), true
+		}
+
+	case durationType:
+		if d, ok := v.(interface{ Duration() time.Duration }); ok {
+			return reflect.ValueOf(d.Duration()), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
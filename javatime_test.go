@@ -0,0 +1,136 @@
+/******************************************************
+# DESC    : javatime.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 20:30
+# FILE    : javatime_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// go test -v -run TestJavaTime
+
+func TestJavaTime(t *testing.T) {
+	var (
+		now    = time.Date(2026, 10, 19, 20, 30, 1, 123456789, time.FixedZone("", 8*3600))
+		values = []Any{
+			NewLocalDate(now),
+			NewLocalTime(now),
+			NewLocalDateTime(now),
+			NewZonedDateTime(now),
+			NewInstant(now),
+			NewDuration(-1500 * time.Millisecond),
+			Timestamp{Value: now.Truncate(time.Millisecond)},
+		}
+	)
+
+	for _, v := range values {
+		var want = reflect.New(reflect.TypeOf(v))
+		want.Elem().Set(reflect.ValueOf(v))
+
+		// the date is decoded in local time zone
+		var local = func(res Any) Any {
+			if ts, ok := res.(*Timestamp); ok {
+				ts.Value = ts.Value.In(now.Location())
+			}
+			return res
+		}
+
+		res, err := NewDecoder(Encode(v, nil)).Decode()
+		if err != nil || !reflect.DeepEqual(local(res), want.Interface()) {
+			t.Errorf("Decode(Encode(%v)) = %#v, error:%v", v, res, err)
+		}
+
+		e := NewEncoder2()
+		if err = e.Encode(v); err != nil {
+			t.Fatalf("Encoder2.Encode(%v) = error:%v", v, err)
+		}
+		res, err = NewDecoder2(e.Buffer()).Decode()
+		if err != nil || !reflect.DeepEqual(local(res), want.Interface()) {
+			t.Errorf("Decoder2.Decode(Encode(%v)) = %#v, error:%v", v, res, err)
+		}
+	}
+
+	if d := NewDuration(-1500 * time.Millisecond); d.Seconds != -2 || d.Nanos != 5e8 || d.Duration() != -1500*time.Millisecond {
+		t.Errorf("NewDuration(-1.5s) = %#v", d)
+	}
+	if z := NewZonedDateTime(now); z.ZoneId != "+08:00" || !z.Time().Equal(now) {
+		t.Errorf("NewZonedDateTime(%v) = %v", now, z)
+	}
+	if id := (ZoneOffset{Seconds: -5*3600 - 1800}).ID(); id != "-05:30" {
+		t.Errorf("ZoneOffset.ID() = %s", id)
+	}
+	if loc, err := time.LoadLocation("Asia/Shanghai"); err == nil {
+		z := NewZonedDateTime(now.In(loc))
+		if z.ZoneId != "Asia/Shanghai" || z.Time().Location().String() != loc.String() || !z.Time().Equal(now) {
+			t.Errorf("NewZonedDateTime(%v) = %v", now.In(loc), z)
+		}
+	}
+
+	// time.Duration is encoded as java.time.Duration
+	var d time.Duration
+	res, err := NewDecoder(Encode(90*time.Second, nil)).Decode()
+	if err != nil || ConvertTo(res, &d) != nil || d != 90*time.Second {
+		t.Errorf("Decode(Encode(90s)) = %#v, error:%v", res, err)
+	}
+
+	// LocalDateTime is converted to time.Time in local time zone
+	var tm time.Time
+	const layout = "2006-01-02 15:04:05.999999999"
+	if err = ConvertTo(&LocalDateTime{}, &tm); err != nil || !tm.Equal(time.Date(0, 0, 0, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("ConvertTo(LocalDateTime{}) = %v, error:%v", tm, err)
+	}
+	if err = ConvertTo(values[2], &tm); err != nil || tm.Format(layout) != now.Format(layout) {
+		t.Errorf("ConvertTo(%v) = %v, error:%v", values[2], tm, err)
+	}
+	for _, v := range values[3:5] {
+		if err = ConvertTo(v, &tm); err != nil || !tm.Equal(now) {
+			t.Errorf("ConvertTo(%v) = %v, error:%v", v, tm, err)
+		}
+	}
+}
+
+// the java provider writes java.sql.Timestamp and java.time.LocalDate without hessian-lite
+func TestJavaTimeObject(t *testing.T) {
+	var (
+		e   = NewEncoder2()
+		now = time.Unix(1760000000, 123e6)
+	)
+
+	e.buffer = append(e.buffer, BC_OBJECT_DEF)
+	e.encString(JAVA_SQL_TIMESTAMP)
+	e.encInt32(1)
+	e.encString("value")
+	e.buffer = append(e.buffer, BC_OBJECT_DIRECT)
+	e.encDate(now)
+
+	e.buffer = append(e.buffer, BC_OBJECT_DEF)
+	e.encString("java.time.LocalDate")
+	e.encInt32(3)
+	for _, field := range []string{"year", "month", "day"} {
+		e.encString(field)
+	}
+	e.buffer = append(e.buffer, BC_OBJECT_DIRECT+1)
+	for _, i := range []int32{2026, 10, 19} {
+		e.encInt32(i)
+	}
+
+	var (
+		tm time.Time
+		d  = NewDecoder2(e.Buffer())
+	)
+	res, err := d.Decode()
+	if err != nil || ConvertTo(res, &tm) != nil || !tm.Equal(now) {
+		t.Errorf("Decode(Timestamp) = %#v, error:%v", res, err)
+	}
+	res, err = d.Decode()
+	if err != nil || !reflect.DeepEqual(res, &LocalDate{Year: 2026, Month: 10, Day: 19}) {
+		t.Errorf("Decode(LocalDate) = %#v, error:%v", res, err)
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by hessiangen -type MathService; DO NOT EDIT.
+
+package hessian
+
+// MathServiceProxy implements MathService by calling the hessian service.
+type MathServiceProxy struct {
+	client *Client
+}
+
+var _ MathService = (*MathServiceProxy)(nil)
+
+func NewMathServiceProxy(client *Client) *MathServiceProxy {
+	return &MathServiceProxy{client: client}
+}
+
+func (this *MathServiceProxy) Add(x int32, y int32) (int32, error) {
+	var ret int32
+	rsp, err := this.client.Invoke("Add", x, y)
+	if err != nil {
+		return ret, err
+	}
+	err = ConvertTo(rsp, &ret)
+	return ret, err
+}
+
+func (this *MathServiceProxy) Div(x int32, y int32) (int32, error) {
+	var ret int32
+	rsp, err := this.client.Invoke("Div", x, y)
+	if err != nil {
+		return ret, err
+	}
+	err = ConvertTo(rsp, &ret)
+	return ret, err
+}
+
+func (this *MathServiceProxy) Sum(l []Any) (int64, error) {
+	var ret int64
+	rsp, err := this.client.Invoke("Sum", l)
+	if err != nil {
+		return ret, err
+	}
+	err = ConvertTo(rsp, &ret)
+	return ret, err
+}
+
+func (this *MathServiceProxy) Echo(foo *Foo) (*Foo, error) {
+	var ret *Foo
+	rsp, err := this.client.Invoke("Echo", foo)
+	if err != nil {
+		return ret, err
+	}
+	err = ConvertTo(rsp, &ret)
+	return ret, err
+}
+
+func (this *MathServiceProxy) Reset() error {
+	_, err := this.client.Invoke("reset")
+	return err
+}
@@ -0,0 +1,115 @@
+/******************************************************
+# DESC    : generic object of unknown java class
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-20 00:30
+# FILE    : object.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the way to decode the object or typed map of unknown java class, which is
+// neither a registered POJO, java collection, java enum nor a java class of
+// registered serializer.
+type UnknownTypePolicy int
+
+const (
+	UNKNOWN_TYPE_MAP    UnknownTypePolicy = iota // map[Any]Any, or *TypedMap by SetTypedMap
+	UNKNOWN_TYPE_OBJECT                          // *Object
+	UNKNOWN_TYPE_ERROR                           // decoding fails
+)
+
+// UnknownTypeHandler decodes the object or typed map of unknown java class
+// @obj.Type, and its return value is the decoded value.
+type UnknownTypeHandler func(obj *Object) (Any, error)
+
+// Object is an object or typed map of unknown java class, whose fields are in
+// the order on the wire. It is encoded as an object of java class Type again,
+// so a gateway can forward it without losing its java class.
+type Object struct {
+	Type   string
+	Fields *OrderedMap
+}
+
+// javaMapTypes are the java map classes which are not registered by
+// RegisterJavaCollection but decoded as maps like java.util.HashMap.
+var javaMapTypes = map[string]struct{}{
+	"java.util.Map":                              {},
+	"java.util.Hashtable":                        {},
+	"java.util.Properties":                       {},
+	"java.util.IdentityHashMap":                  {},
+	"java.util.WeakHashMap":                      {},
+	"java.util.EnumMap":                          {},
+	"java.util.concurrent.ConcurrentHashMap":     {},
+	"java.util.concurrent.ConcurrentSkipListMap": {},
+}
+
+func NewObject(javaType string) *Object {
+	return &Object{Type: javaType, Fields: NewOrderedMap()}
+}
+
+// objectFields returns the field names and values of @v, whose keys should
+// be strings.
+func objectFields(v *Object) ([]string, []Any, error) {
+	var (
+		err    error
+		names  []string
+		values []Any
+	)
+
+	if v.Fields == nil {
+		return nil, nil, nil
+	}
+	v.Fields.Range(func(key Any, value Any) bool {
+		name, ok := key.(string)
+		if !ok {
+			err = fmt.Errorf("the field name of java class %s should be a string, but it is %T", v.Type, key)
+			return false
+		}
+		names = append(names, name)
+		values = append(values, value)
+		return true
+	})
+
+	return names, values, err
+}
+
+// isUnknownType checks whether the object or map of java class @javaType is
+// decoded by the unknown type policy if it is not a registered POJO, which is
+// checked by the caller with the POJO instance created by the registry.
+func isUnknownType(javaType string) bool {
+	if javaType == "" || strings.HasPrefix(javaType, "[") {
+		return false
+	}
+	if _, ok := getCollectionType(javaType); ok {
+		return false
+	}
+	if _, ok := javaMapTypes[javaType]; ok {
+		return false
+	}
+
+	return !isJavaObject(javaType)
+}
+
+// isUnknownTypeObject checks whether the object or map of unknown java class
+// is decoded as *Object before @policy or @handler is applied to it.
+func isUnknownTypeObject(policy UnknownTypePolicy, handler UnknownTypeHandler) bool {
+	return handler != nil || policy != UNKNOWN_TYPE_MAP
+}
+
+// toUnknownType applies @handler, or @policy if @handler is nil, to @obj.
+func toUnknownType(obj *Object, policy UnknownTypePolicy, handler UnknownTypeHandler) (Any, error) {
+	if handler != nil {
+		return handler(obj)
+	}
+	if policy == UNKNOWN_TYPE_ERROR {
+		return nil, fmt.Errorf("unknown java class %s", obj.Type)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,117 @@
+/******************************************************
+# DESC    : object.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-20 00:30
+# FILE    : object_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// go test -v -run TestUnknownType
+
+func TestUnknownType(t *testing.T) {
+	// the object of java class com.foo.Unknown sent by java
+	var e = NewEncoder2()
+	e.encObjectFields("com.foo.Unknown", []string{"b", "a"}, []Any{"x", int32(1)})
+	var (
+		obj = NewObject("com.foo.Unknown")
+		buf = e.Buffer()
+	)
+	obj.Fields.Set("b", "x")
+	obj.Fields.Set("a", int32(1))
+
+	var cases = []struct {
+		policy  UnknownTypePolicy
+		handler UnknownTypeHandler
+		want    Any
+	}{
+		{UNKNOWN_TYPE_MAP, nil, map[Any]Any{"a": int32(1), "b": "x"}},
+		{UNKNOWN_TYPE_OBJECT, nil, obj},
+		{UNKNOWN_TYPE_ERROR, nil, nil},
+		{UNKNOWN_TYPE_ERROR, func(o *Object) (Any, error) { return o.Type, nil }, "com.foo.Unknown"},
+	}
+	for _, c := range cases {
+		d := NewDecoder2(buf)
+		d.SetUnknownTypePolicy(c.policy)
+		d.SetUnknownTypeHandler(c.handler)
+		v, err := d.Decode()
+		if c.want == nil {
+			if err == nil {
+				t.Errorf("Decode(policy:%d) = %#v, want error", c.policy, v)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decode(policy:%d) = %#v, error:%v, want %#v", c.policy, v, err, c.want)
+		}
+	}
+
+	// *Object is encoded as the object of its java class again
+	e = NewEncoder2()
+	if err := e.Encode(obj); err != nil || !bytes.Equal(e.Buffer(), buf) {
+		t.Errorf("Encoder2.Encode(%#v) = %s, error:%v, want %s", obj, SprintHex(e.Buffer()), err, SprintHex(buf))
+	}
+	if desc, err := GetTypeDesc(obj); err != nil || desc != "Lcom/foo/Unknown;" {
+		t.Errorf("GetTypeDesc(%#v) = %s, error:%v", obj, desc, err)
+	}
+
+	// the typed maps of unknown java class
+	var tm = &TypedMap{Type: "com.foo.Unknown", Map: obj.Fields}
+	d := NewDecoder(Encode(tm, nil))
+	d.SetUnknownTypePolicy(UNKNOWN_TYPE_OBJECT)
+	if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, obj) {
+		t.Errorf("Decode(%#v) = %#v, error:%v", tm, v, err)
+	}
+	if b := Encode(obj, nil); !bytes.Equal(b, Encode(tm, nil)) {
+		t.Errorf("Encode(%#v) = %s", obj, SprintHex(b))
+	}
+	e = NewEncoder2()
+	e.Encode(tm)
+	d2 := NewDecoder2(e.Buffer())
+	d2.SetUnknownTypePolicy(UNKNOWN_TYPE_ERROR)
+	if v, err := d2.Decode(); err == nil {
+		t.Errorf("Decoder2.Decode(%#v) = %#v, want error", tm, v)
+	}
+
+	// the policy does not affect the registered java classes and untyped maps
+	for _, v := range []Any{
+		Account{Name: "alex"},
+		map[Any]Any{"a": int32(1)},
+		&TypedMap{Type: JAVA_TREE_MAP, Map: map[Any]Any{"a": int32(1)}},
+		&TypedMap{Type: "java.util.concurrent.ConcurrentHashMap", Map: map[Any]Any{"a": int32(1)}},
+		&TypedMap{Type: "java.util.Hashtable", Map: map[Any]Any{int32(1): "a"}},
+		RED,
+	} {
+		e = NewEncoder2()
+		e.Encode(v)
+		d2 = NewDecoder2(e.Buffer())
+		d2.SetUnknownTypePolicy(UNKNOWN_TYPE_ERROR)
+		if res, err := d2.Decode(); err != nil {
+			t.Errorf("Decoder2.Decode(%#v) = %#v, error:%v", v, res, err)
+		}
+	}
+
+	// the typed map of unknown java class with non-string keys is encoded as
+	// a typed map again
+	tm = &TypedMap{Type: "com.foo.IntMap", Map: map[int32]string{1: "a"}}
+	e = NewEncoder2()
+	e.Encode(tm)
+	buf = e.Buffer()
+	d2 = NewDecoder2(buf)
+	d2.SetUnknownTypePolicy(UNKNOWN_TYPE_OBJECT)
+	v, err := d2.Decode()
+	if o, ok := v.(*Object); err != nil || !ok || o.Type != "com.foo.IntMap" {
+		t.Fatalf("Decoder2.Decode(%#v) = %#v, error:%v", tm, v, err)
+	}
+	e = NewEncoder2()
+	if err = e.Encode(v); err != nil || !bytes.Equal(e.Buffer(), buf) {
+		t.Errorf("Encoder2.Encode(%#v) = %s, error:%v, want %s", v, SprintHex(e.Buffer()), err, SprintHex(buf))
+	}
+}
@@ -11,68 +11,301 @@ package hessian
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
 )
 
 var (
-	pojoReg = POJORegistry{registry: make(map[string]reflect.Type)}
+	// the global registry of RegisterPOJO, which is used by the decoders and
+	// clients without their own registries
+	pojoReg = NewPOJORegistry()
+	// the POJOs of this package, such as BigDecimal and LocalDate, which are
+	// registered in every registry
+	builtinPOJOs = make(map[string]reflect.Type)
+	// reflect.Type -> map[string]pojoSetter
+	pojoSetters sync.Map
+	// reflect.Type -> *pojoPlan
+	pojoPlans sync.Map
 )
 
 type POJO interface {
 	GetType() string
 }
 
+// pojoPlan is the encoding plan of a POJO type, which is computed once.
+type pojoPlan struct {
+	names   []string // the field names, such as GetName -> name
+	getters []int    // the indexes of the "Get..." methods of the fields
+}
+
+// pojoSetter is the "Set..." method of a POJO type.
+type pojoSetter struct {
+	index int          // the index of the method
+	arg   reflect.Type // the type of the argument
+}
+
+// POJORegistry maps the java classes to the go types of POJO.
+// The map is copied on write, so the lookups of the decoders take no lock.
 type POJORegistry struct {
-	sync.Mutex
-	registry map[string]reflect.Type
+	sync.Mutex              // serializes the writers
+	registry   atomic.Value // map[string]reflect.Type
 }
 
-// 解析struct
-func showPOJORegistry() {
-	pojoReg.Lock()
-	for k, v := range pojoReg.registry {
-		fmt.Println("-->> show Registered types <<----")
-		fmt.Println(k, v)
+// NewPOJORegistry returns a registry which has no POJO except the ones of
+// this package, such as BigDecimal and LocalDate. It can be set to the
+// decoders and clients by their SetPOJORegistry, so two subsystems can map
+// the same java class to different go types.
+func NewPOJORegistry() *POJORegistry {
+	var (
+		r = &POJORegistry{}
+		m = make(map[string]reflect.Type, len(builtinPOJOs))
+	)
+	for k, v := range builtinPOJOs {
+		m[k] = v
 	}
-	pojoReg.Unlock()
+	r.registry.Store(m)
+
+	return r
 }
 
+// DefaultPOJORegistry returns the global registry of RegisterPOJO.
+func DefaultPOJORegistry() *POJORegistry {
+	return pojoReg
+}
+
+// load returns the current map of the registry, which must not be modified.
+func (this *POJORegistry) load() map[string]reflect.Type {
+	return this.registry.Load().(map[string]reflect.Type)
+}
+
+// update calls @f with a copy of the map of the registry, and replaces the
+// map with the copy.
+func (this *POJORegistry) update(f func(m map[string]reflect.Type)) {
+	this.Lock()
+	var (
+		old = this.load()
+		m   = make(map[string]reflect.Type, len(old)+1)
+	)
+	for k, v := range old {
+		m[k] = v
+	}
+	f(m)
+	this.registry.Store(m)
+	this.Unlock()
+}
+
+// RegisterPOJO registers @o in the global registry.
 // the return value is false if @o has been registered.
 func RegisterPOJO(o POJO) bool {
+	return pojoReg.RegisterPOJO(o)
+}
+
+// RegisterPOJO registers @o as its java class.
+// the return value is false if @o has been registered.
+func (this *POJORegistry) RegisterPOJO(o POJO) bool {
 	var ok bool
-	pojoReg.Lock()
-	if _, ok = pojoReg.registry[o.GetType()]; !ok {
-		pojoReg.registry[o.GetType()] = reflect.TypeOf(o)
-	}
-	pojoReg.Unlock()
+	this.update(func(m map[string]reflect.Type) {
+		if _, ok = m[o.GetType()]; !ok {
+			m[o.GetType()] = reflect.TypeOf(o)
+		}
+	})
 
 	return !ok
 }
 
-// check if @typeName has been registered or not.
-func checkPOJORegistry(typeName string) bool {
+// registerPOJOAs registers @o as java class @typeName as well, so the object
+// of java class @typeName is decoded as the type of @o.
+func (this *POJORegistry) registerPOJOAs(typeName string, o POJO) {
+	this.update(func(m map[string]reflect.Type) {
+		m[typeName] = reflect.TypeOf(o)
+	})
+}
+
+// RegisterAlias registers @alias as another name of the registered java class
+// @javaType, such as the old package name of the class in a rolling migration,
+// so the objects of both names are decoded as the same go type.
+func (this *POJORegistry) RegisterAlias(alias string, javaType string) error {
+	var err error
+	this.update(func(m map[string]reflect.Type) {
+		typ, ok := m[javaType]
+		if !ok {
+			err = fmt.Errorf("java class %s is not registered", javaType)
+			return
+		}
+		if t, ok := m[alias]; ok && t != typ {
+			err = fmt.Errorf("java class %s has been registered as %s", alias, t)
+			return
+		}
+		m[alias] = typ
+	})
+
+	return err
+}
+
+// UnregisterPOJO removes java class @javaType, which may be an alias.
+// The return value is false if @javaType is not registered.
+func (this *POJORegistry) UnregisterPOJO(javaType string) bool {
 	var ok bool
-	pojoReg.Lock()
-	_, ok = pojoReg.registry[typeName]
-	pojoReg.Unlock()
+	this.update(func(m map[string]reflect.Type) {
+		_, ok = m[javaType]
+		delete(m, javaType)
+	})
 
 	return ok
 }
 
-// Create a new instance whose type name is @t.
-// the return value is nil if @o has been registered.
-func createInstance(typeName string) interface{} {
+// JavaTypes returns the sorted names of the registered java classes,
+// including the aliases.
+func (this *POJORegistry) JavaTypes() []string {
 	var (
-		ok  bool
-		typ reflect.Type
+		m     = this.load()
+		names = make([]string, 0, len(m))
 	)
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// GoType returns the go type which java class @javaType is registered as.
+func (this *POJORegistry) GoType(javaType string) (reflect.Type, bool) {
+	typ, ok := this.load()[javaType]
+	return typ, ok
+}
+
+// JavaType returns the java class which go type @typ, or the type that @typ
+// points to, is registered as. It is the name returned by GetType() if it is
+// registered, or else the first one of the sorted aliases.
+func (this *POJORegistry) JavaType(typ reflect.Type) (string, bool) {
+	var names []string
+
+	if typ == nil {
+		return "", false
+	}
+	for name, t := range this.load() {
+		if t == typ || (typ.Kind() == reflect.Ptr && t == typ.Elem()) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
 
-	pojoReg.Lock()
-	typ, ok = pojoReg.registry[typeName]
-	pojoReg.Unlock()
+	if typ.Implements(pojoType) {
+		var name = getPOJOType(typ)
+		for _, n := range names {
+			if n == name {
+				return name, true
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names[0], true
+}
+
+// registerBuiltinPOJO registers the POJO @o of this package as its java class
+// and @typeNames in the global registry and the new registries.
+func registerBuiltinPOJO(o POJO, typeNames ...string) {
+	for _, name := range append([]string{o.GetType()}, typeNames...) {
+		builtinPOJOs[name] = reflect.TypeOf(o)
+		pojoReg.registerPOJOAs(name, o)
+	}
+}
+
+// getPOJOType returns the java type name of @typ which implements POJO.
+func getPOJOType(typ reflect.Type) string {
+	var v = reflect.Zero(typ)
+	if typ.Kind() == reflect.Ptr {
+		v = reflect.New(typ.Elem())
+	}
+
+	return v.Interface().(POJO).GetType()
+}
+
+// check if @typeName has been registered or not.
+func (this *POJORegistry) checkPOJORegistry(typeName string) bool {
+	_, ok := this.load()[typeName]
+	return ok
+}
+
+// Create a new instance whose type name is @t.
+// the return value is nil if @o has been registered.
+func (this *POJORegistry) createInstance(typeName string) interface{} {
+	typ, ok := this.load()[typeName]
 	if !ok {
 		return nil
 	}
 
 	return reflect.New(typ).Interface()
 }
+
+// getPOJOPlan returns the encoding plan of POJO type @typ, whose fields are
+// got by the "Get..." methods except GetType, and caches it in pojoPlans.
+func getPOJOPlan(typ reflect.Type) *pojoPlan {
+	if plan, ok := pojoPlans.Load(typ); ok {
+		return plan.(*pojoPlan)
+	}
+
+	var plan = &pojoPlan{}
+	for i := 0; i < typ.NumMethod(); i++ {
+		var method = typ.Method(i)
+		if len(method.Name) <= 3 || !strings.HasPrefix(method.Name, "Get") {
+			continue
+		}
+		if method.Name == "GetType" || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 {
+			continue
+		}
+		plan.names = append(plan.names, lowerFirst(method.Name[3:]))
+		plan.getters = append(plan.getters, i)
+	}
+	pojoPlans.Store(typ, plan)
+
+	return plan
+}
+
+// getPOJOSetters returns the "Set..." methods of POJO type @typ, which are
+// mapped by the method names without "Set" and cached in pojoSetters.
+func getPOJOSetters(typ reflect.Type) map[string]pojoSetter {
+	if setters, ok := pojoSetters.Load(typ); ok {
+		return setters.(map[string]pojoSetter)
+	}
+
+	var setters = make(map[string]pojoSetter)
+	for i := 0; i < typ.NumMethod(); i++ {
+		var method = typ.Method(i)
+		if len(method.Name) > 3 && strings.HasPrefix(method.Name, "Set") && method.Type.NumIn() == 2 {
+			setters[method.Name[3:]] = pojoSetter{index: i, arg: method.Type.In(1)}
+		}
+	}
+	pojoSetters.Store(typ, setters)
+
+	return setters
+}
+
+// getPOJORegistry returns @r, or the global registry if @r is nil.
+func getPOJORegistry(r *POJORegistry) *POJORegistry {
+	if r == nil {
+		return pojoReg
+	}
+
+	return r
+}
+
+// field name of method "GetXxx" or "SetXxx": Xxx -> xxx
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+// method name of field xxx: xxx -> Xxx
+func upperFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
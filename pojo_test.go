@@ -0,0 +1,267 @@
+/******************************************************
+# DESC    : pojo.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 23:50
+# FILE    : pojo_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// go test -v -run TestPOJORegistry
+
+type Van struct {
+	Name string
+}
+
+func (Van) GetType() string        { return "test.Van" }
+func (c Van) GetName() string      { return c.Name }
+func (c *Van) SetName(name string) { c.Name = name }
+
+// Truck is another go type of java class test.Van
+type Truck struct {
+	Name string
+}
+
+func (Truck) GetType() string        { return "test.Van" }
+func (t Truck) GetName() string      { return t.Name }
+func (t *Truck) SetName(name string) { t.Name = name }
+
+func TestPOJORegistry(t *testing.T) {
+	var (
+		vans   = NewPOJORegistry()
+		trucks = NewPOJORegistry()
+	)
+
+	if !vans.RegisterPOJO(Van{}) || vans.RegisterPOJO(Van{}) || !trucks.RegisterPOJO(Truck{}) {
+		t.Fatalf("RegisterPOJO() failed")
+	}
+	if pojoReg.checkPOJORegistry("test.Van") {
+		t.Errorf("test.Van is registered in the global registry")
+	}
+
+	var cases = []struct {
+		r    *POJORegistry
+		v    Any
+		want Any
+	}{
+		{vans, Van{Name: "a"}, &Van{Name: "a"}},
+		{trucks, Van{Name: "a"}, &Truck{Name: "a"}},
+		{nil, Van{Name: "a"}, map[Any]Any{"name": "a"}},
+		{vans, []Van{{Name: "b"}}, []*Van{{Name: "b"}}},
+		{trucks, []Van{{Name: "b"}}, []*Truck{{Name: "b"}}},
+		// the POJOs of this package are registered in every registry
+		{vans, BigDecimal{Value: "1.5"}, &BigDecimal{Value: "1.5"}},
+	}
+	for _, c := range cases {
+		d := NewDecoder(Encode(c.v, nil))
+		d.SetPOJORegistry(c.r)
+		v, err := d.Decode()
+		if p, ok := v.(*map[Any]Any); ok {
+			v = *p
+		}
+		if err != nil || !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decode(Encode(%#v)) = %#v, error:%v, want %#v", c.v, v, err, c.want)
+		}
+
+		e := NewEncoder2()
+		if err = e.Encode(c.v); err != nil {
+			t.Fatalf("Encoder2.Encode(%#v) = error:%v", c.v, err)
+		}
+		d2 := NewDecoder2(e.Buffer())
+		d2.SetPOJORegistry(c.r)
+		if v, err = d2.Decode(); err != nil || !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decoder2.Decode(Encode(%#v)) = %#v, error:%v, want %#v", c.v, v, err, c.want)
+		}
+	}
+}
+
+// go test -v -run TestPOJORegistryAlias
+
+func TestPOJORegistryAlias(t *testing.T) {
+	var r = NewPOJORegistry()
+
+	r.RegisterPOJO(Van{})
+	if err := r.RegisterAlias("old.Van", "test.Van"); err != nil {
+		t.Fatalf("RegisterAlias() = error:%v", err)
+	}
+	if err := r.RegisterAlias("test.Van", "old.Van"); err != nil {
+		t.Errorf("RegisterAlias(the same type) = error:%v", err)
+	}
+	if err := r.RegisterAlias("test.Van", "java.math.BigDecimal"); err == nil {
+		t.Errorf("RegisterAlias(the other type) = nil")
+	}
+	if err := r.RegisterAlias("new.Van", "unknown.Van"); err == nil {
+		t.Errorf("RegisterAlias(unregistered) = nil")
+	}
+
+	var names = r.JavaTypes()
+	if !sort.StringsAreSorted(names) || len(names) != len(builtinPOJOs)+2 {
+		t.Errorf("JavaTypes() = %v", names)
+	}
+	for _, typ := range []reflect.Type{reflect.TypeOf(Van{}), reflect.TypeOf(&Van{})} {
+		if name, ok := r.JavaType(typ); !ok || name != "test.Van" {
+			t.Errorf("JavaType(%s) = %s", typ, name)
+		}
+	}
+	if typ, ok := r.GoType("old.Van"); !ok || typ != reflect.TypeOf(Van{}) {
+		t.Errorf("GoType(old.Van) = %v", typ)
+	}
+
+	// the objects of the old name are decoded as Van
+	e := NewEncoder2()
+	e.Encode(&TypedMap{Type: "old.Van", Map: map[string]string{"name": "a"}})
+	d := NewDecoder2(e.Buffer())
+	d.SetPOJORegistry(r)
+	if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, &Van{Name: "a"}) {
+		t.Errorf("Decode(old.Van) = %#v, error:%v", v, err)
+	}
+
+	if !r.UnregisterPOJO("test.Van") || r.UnregisterPOJO("test.Van") {
+		t.Errorf("UnregisterPOJO(test.Van) failed")
+	}
+	if name, ok := r.JavaType(reflect.TypeOf(Van{})); !ok || name != "old.Van" {
+		t.Errorf("JavaType(Van) = %s, want old.Van", name)
+	}
+	r.UnregisterPOJO("old.Van")
+	if name, ok := r.JavaType(reflect.TypeOf(Van{})); ok {
+		t.Errorf("JavaType(Van) = %s", name)
+	}
+	if name, ok := r.JavaType(nil); ok {
+		t.Errorf("JavaType(nil) = %s", name)
+	}
+	// **Van, which points to the registered *Van, does not implement POJO
+	r.RegisterPOJO(&Van{})
+	if name, ok := r.JavaType(reflect.TypeOf((**Van)(nil))); !ok || name != "test.Van" {
+		t.Errorf("JavaType(**Van) = %s", name)
+	}
+	if DefaultPOJORegistry() != pojoReg {
+		t.Errorf("DefaultPOJORegistry() is not the global registry")
+	}
+}
+
+// go test -v -race -run TestPOJORegistryConcurrency
+
+func TestPOJORegistryConcurrency(t *testing.T) {
+	var (
+		wg sync.WaitGroup
+		r  = NewPOJORegistry()
+		e  = NewEncoder2()
+	)
+
+	r.RegisterPOJO(Van{})
+	e.Encode(Van{Name: "a"})
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.RegisterAlias(fmt.Sprintf("old%d.Van%d", i, j), "test.Van")
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				d := NewDecoder2(e.Buffer())
+				d.SetPOJORegistry(r)
+				if v, err := d.Decode(); err != nil || !reflect.DeepEqual(v, &Van{Name: "a"}) {
+					t.Errorf("Decode() = %#v, error:%v", v, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(r.JavaTypes()); n != len(builtinPOJOs)+1+400 {
+		t.Errorf("len(JavaTypes()) = %d", n)
+	}
+
+	// test.Van is decoded as *Van or a map while it is unregistered and
+	// registered again concurrently
+	var buf = Encode(Van{Name: "a"}, nil)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 1000; j++ {
+			r.UnregisterPOJO("test.Van")
+			r.RegisterPOJO(Van{})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 1000; j++ {
+			d := NewDecoder(buf)
+			d.SetPOJORegistry(r)
+			if _, err := d.Decode(); err != nil {
+				t.Errorf("Decode() = error:%v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+// go test -bench POJO -cpu 1,2,4,8
+
+func BenchmarkPOJORegistry(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if pojoReg.createInstance("test.Account") == nil {
+				b.Fatal("test.Account is not registered")
+			}
+		}
+	})
+}
+
+func BenchmarkDecodePOJO(b *testing.B) {
+	var buf = Encode(Account{Name: "alex"}, nil)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := NewDecoder(buf).Decode(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecoder2POJO(b *testing.B) {
+	var e = NewEncoder2()
+	e.Encode(Account{Name: "alex"})
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := NewDecoder2(e.Buffer()).Decode(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// go test -v -run TestPOJOPlan
+
+func TestPOJOPlan(t *testing.T) {
+	var plan = getPOJOPlan(reflect.TypeOf(Account{}))
+	if want := []string{"balance", "name"}; !reflect.DeepEqual(plan.names, want) {
+		t.Errorf("getPOJOPlan(Account).names = %v, want %v", plan.names, want)
+	}
+	if getPOJOPlan(reflect.TypeOf(Account{})) != plan {
+		t.Errorf("the plan of Account is not cached")
+	}
+
+	typ, names, values := getPOJOFields(Van{Name: "a"})
+	if typ != "test.Van" || !reflect.DeepEqual(names, []string{"name"}) || !reflect.DeepEqual(values, []Any{"a"}) {
+		t.Errorf("getPOJOFields(Van) = %s, %v, %v", typ, names, values)
+	}
+}
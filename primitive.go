@@ -0,0 +1,81 @@
+/******************************************************
+# DESC    : java primitive types without go counterparts
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 22:40
+# FILE    : primitive.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf16"
+)
+
+var (
+	javaCharType  = reflect.TypeOf(JavaChar(0))
+	javaCharsType = reflect.TypeOf([]JavaChar(nil))
+)
+
+// JavaChar is a java char, a UTF-16 code unit, whose jvm type descriptor is
+// "C" and "Ljava/lang/Character;" for *JavaChar. It is encoded as a string of
+// one char, and []JavaChar is encoded as a string like java char[].
+// The string of one char is converted to JavaChar by ConvertTo.
+type JavaChar uint16
+
+// JavaShort is a java short, which is encoded as an int. Its descriptor is
+// "S" and "Ljava/lang/Short;" for *JavaShort.
+type JavaShort int16
+
+// JavaByte is a java byte, which is encoded as an int. Its descriptor is
+// "B" and "Ljava/lang/Byte;" for *JavaByte.
+type JavaByte int8
+
+// String returns the char, or U+FFFD if it is a surrogate.
+func (c JavaChar) String() string {
+	return string(rune(c))
+}
+
+// javaCharsString converts java char[] @chars to string.
+func javaCharsString(chars []JavaChar) string {
+	var s = make([]uint16, len(chars))
+	for i, c := range chars {
+		s[i] = uint16(c)
+	}
+
+	return string(utf16.Decode(s))
+}
+
+// convertJavaChar converts the decoded string @v to JavaChar or []JavaChar of
+// type @typ. The return value ok is false if @v is not a string or @typ is
+// neither JavaChar nor []JavaChar.
+func convertJavaChar(v Any, typ reflect.Type) (rv reflect.Value, ok bool, err error) {
+	var s string
+
+	if s, ok = v.(string); !ok {
+		return rv, false, nil
+	}
+
+	switch typ {
+	case javaCharType:
+		var chars = utf16.Encode([]rune(s))
+		if len(chars) != 1 {
+			return rv, true, fmt.Errorf("can not convert string %q to java char", s)
+		}
+		return reflect.ValueOf(JavaChar(chars[0])), true, nil
+
+	case javaCharsType:
+		var (
+			units = utf16.Encode([]rune(s))
+			chars = make([]JavaChar, len(units))
+		)
+		for i, c := range units {
+			chars[i] = JavaChar(c)
+		}
+		return reflect.ValueOf(chars), true, nil
+	}
+
+	return rv, false, nil
+}
@@ -0,0 +1,66 @@
+/******************************************************
+# DESC    : primitive.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 22:40
+# FILE    : primitive_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// go test -v -run TestJava
+
+func TestJavaPrimitive(t *testing.T) {
+	var (
+		c = JavaChar('a')
+		s = JavaShort(-2)
+		b = JavaByte(3)
+	)
+
+	desc, err := GetParamTypes(c, &c, s, &s, b, &b, []JavaChar{c})
+	if want := "CLjava/lang/Character;SLjava/lang/Short;BLjava/lang/Byte;[C"; err != nil || desc != want {
+		t.Errorf("GetParamTypes() = %s, error:%v, want %s", desc, err, want)
+	}
+
+	var cases = []struct {
+		v    Any
+		want Any
+	}{
+		{c, "a"},
+		{[]JavaChar{'h', 0xd83d, 0xde00}, "h\U0001F600"},
+		{s, int32(-2)},
+		{b, int32(3)},
+	}
+	for _, c := range cases {
+		if got, want := Encode(c.v, nil), Encode(c.want, nil); !bytes.Equal(got, want) {
+			t.Errorf("Encode(%#v) = %s, want %s", c.v, SprintHex(got), SprintHex(want))
+		}
+
+		var e1, e2 = NewEncoder2(), NewEncoder2()
+		e1.Encode(c.v)
+		e2.Encode(c.want)
+		if !bytes.Equal(e1.Buffer(), e2.Buffer()) {
+			t.Errorf("Encoder2.Encode(%#v) = %s, want %s", c.v, SprintHex(e1.Buffer()), SprintHex(e2.Buffer()))
+		}
+
+		// the decoded value is converted back
+		var v = reflect.New(reflect.TypeOf(c.v))
+		res, err := NewDecoder2(e1.Buffer()).Decode()
+		if err != nil || ConvertTo(res, v.Interface()) != nil || !reflect.DeepEqual(v.Elem().Interface(), c.v) {
+			t.Errorf("ConvertTo(%#v) = %#v, error:%v", res, v.Elem().Interface(), err)
+		}
+	}
+
+	if err = ConvertTo("ab", &c); err == nil {
+		t.Errorf("ConvertTo(ab) = %v", c)
+	}
+	if err = ConvertTo(int32(300), &b); err == nil {
+		t.Errorf("ConvertTo(300) = %v", b)
+	}
+}
@@ -0,0 +1,181 @@
+/******************************************************
+# DESC    : custom serializer of go types
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 23:20
+# FILE    : serializer.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// HessianMarshaler is implemented by the go type which encodes itself as
+// another value, such as a string, a POJO or a TypedMap, so it works with
+// both hessian 1.0 and 2.0.
+type HessianMarshaler interface {
+	MarshalHessian() (Any, error)
+}
+
+// HessianUnmarshaler is implemented by the go type which decodes itself from
+// a decoded value, such as the string or the map of the fields of an object.
+// It is called by ConvertTo and the "Set..." methods of POJO.
+type HessianUnmarshaler interface {
+	UnmarshalHessian(v Any) error
+}
+
+// Serializer encodes and decodes the values of a go type which we don't own,
+// such as uuid.UUID, net.IP and decimal.Decimal, like HessianMarshaler and
+// HessianUnmarshaler.
+type Serializer interface {
+	// Marshal converts @v of the go type to a value which can be encoded.
+	Marshal(v Any) (Any, error)
+	// Unmarshal converts the decoded value @v to the go type.
+	Unmarshal(v Any) (Any, error)
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*HessianMarshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*HessianUnmarshaler)(nil)).Elem()
+
+	serializerReg = SerializerRegistry{
+		goTypes:   make(map[reflect.Type]*javaSerializer),
+		javaTypes: make(map[string]*javaSerializer),
+	}
+)
+
+type javaSerializer struct {
+	goType   reflect.Type
+	javaType string
+	Serializer
+}
+
+// SerializerRegistry maps the go types to their serializers.
+type SerializerRegistry struct {
+	sync.RWMutex
+	goTypes   map[reflect.Type]*javaSerializer
+	javaTypes map[string]*javaSerializer
+}
+
+// RegisterSerializer registers @s as the serializer of the type of @v.
+// The object or map of java class @javaType, if it is not empty, is decoded
+// by @s from the map of its fields, such as
+//
+//	RegisterSerializer(uuid.UUID{}, "java.util.UUID", uuidSerializer{})
+//
+// where uuidSerializer.Marshal returns a TypedMap of java.util.UUID whose
+// fields are mostSigBits and leastSigBits, and Unmarshal converts the map of
+// the fields back to uuid.UUID. @javaType is also the java type of the go
+// type in the jvm type descriptors of dubbo invocation.
+func RegisterSerializer(v Any, javaType string, s Serializer) error {
+	var typ = reflect.TypeOf(v)
+	if typ == nil || s == nil {
+		return fmt.Errorf("illegal serializer of %T", v)
+	}
+
+	var js = &javaSerializer{goType: typ, javaType: javaType, Serializer: s}
+	serializerReg.Lock()
+	serializerReg.goTypes[typ] = js
+	if javaType != "" {
+		serializerReg.javaTypes[javaType] = js
+	}
+	serializerReg.Unlock()
+
+	return nil
+}
+
+// getSerializer returns the serializer of go type @typ.
+func getSerializer(typ reflect.Type) (*javaSerializer, bool) {
+	serializerReg.RLock()
+	s, ok := serializerReg.goTypes[typ]
+	serializerReg.RUnlock()
+
+	return s, ok
+}
+
+// getJavaSerializer returns the serializer of java class @javaType.
+func getJavaSerializer(javaType string) (*javaSerializer, bool) {
+	serializerReg.RLock()
+	s, ok := serializerReg.javaTypes[javaType]
+	serializerReg.RUnlock()
+
+	return s, ok
+}
+
+// marshal converts @v to the value to be encoded if @v is a HessianMarshaler
+// or its type has a registered serializer. The return value ok is false if
+// @v is neither.
+func marshal(v Any) (res Any, ok bool, err error) {
+	if m, isMarshaler := v.(HessianMarshaler); isMarshaler {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, true, nil
+		}
+		res, err = m.MarshalHessian()
+		return res, true, err
+	}
+	if s, isRegistered := getSerializer(reflect.TypeOf(v)); isRegistered {
+		res, err = s.Marshal(v)
+		return res, true, err
+	}
+
+	return nil, false, nil
+}
+
+// unmarshal converts the decoded value @v to type @typ if @typ or its pointer
+// is a HessianUnmarshaler, or @typ has a registered serializer. The return
+// value ok is false if it is neither.
+func unmarshal(v Any, typ reflect.Type) (rv reflect.Value, ok bool, err error) {
+	if s, isRegistered := getSerializer(typ); isRegistered {
+		var res Any
+		if res, err = s.Unmarshal(v); err != nil {
+			return rv, true, err
+		}
+		if res == nil || !reflect.TypeOf(res).AssignableTo(typ) {
+			return rv, true, fmt.Errorf("the serializer of %s returns %T", typ, res)
+		}
+		return reflect.ValueOf(res), true, nil
+	}
+
+	switch {
+	case reflect.PtrTo(typ).Implements(unmarshalerType):
+		rv = reflect.New(typ)
+		err = rv.Interface().(HessianUnmarshaler).UnmarshalHessian(v)
+		return rv.Elem(), true, err
+
+	case typ.Kind() == reflect.Ptr && typ.Implements(unmarshalerType):
+		rv = reflect.New(typ.Elem())
+		err = rv.Interface().(HessianUnmarshaler).UnmarshalHessian(v)
+		return rv, true, err
+	}
+
+	return rv, false, nil
+}
+
+// toJavaObject converts the decoded object or map @v of java class @javaType
+// to the go value if @javaType is a java enum or it has a registered serializer.
+// The return value ok is false if it is neither.
+func toJavaObject(v map[Any]Any, javaType string) (Any, bool, error) {
+	if res, ok, err := toEnum(v, javaType); ok {
+		return res, ok, err
+	}
+	if s, ok := getJavaSerializer(javaType); ok {
+		res, err := s.Unmarshal(v)
+		return res, true, err
+	}
+
+	return nil, false, nil
+}
+
+// isJavaObject checks whether the map of java class @javaType is decoded as a
+// go value by toJavaObject.
+func isJavaObject(javaType string) bool {
+	if _, ok := getJavaEnum(javaType); ok {
+		return true
+	}
+	_, ok := getJavaSerializer(javaType)
+	return ok
+}
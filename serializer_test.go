@@ -0,0 +1,178 @@
+/******************************************************
+# DESC    : serializer.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 23:20
+# FILE    : serializer_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// go test -v -run TestSerializer
+
+// UUID is like uuid.UUID, which is serialized as java.util.UUID
+type UUID [16]byte
+
+type uuidSerializer struct{}
+
+func (uuidSerializer) Marshal(v Any) (Any, error) {
+	var u = v.(UUID)
+	return &TypedMap{
+		Type: "java.util.UUID",
+		Map: map[string]int64{
+			"mostSigBits":  int64(binary.BigEndian.Uint64(u[:8])),
+			"leastSigBits": int64(binary.BigEndian.Uint64(u[8:])),
+		},
+	}, nil
+}
+
+func (uuidSerializer) Unmarshal(v Any) (Any, error) {
+	var (
+		u     UUID
+		m, ok = v.(map[Any]Any)
+	)
+	if !ok {
+		return nil, fmt.Errorf("illegal java.util.UUID %#v", v)
+	}
+	most, ok1 := m["mostSigBits"].(int64)
+	least, ok2 := m["leastSigBits"].(int64)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("illegal java.util.UUID %#v", v)
+	}
+	binary.BigEndian.PutUint64(u[:8], uint64(most))
+	binary.BigEndian.PutUint64(u[8:], uint64(least))
+
+	return u, nil
+}
+
+// net.IP is serialized as string
+type ipSerializer struct{}
+
+func (ipSerializer) Marshal(v Any) (Any, error) {
+	return v.(net.IP).String(), nil
+}
+
+func (ipSerializer) Unmarshal(v Any) (Any, error) {
+	var s, _ = v.(string)
+	if ip := net.ParseIP(s); ip != nil {
+		return ip, nil
+	}
+	return nil, fmt.Errorf("illegal ip %#v", v)
+}
+
+// Money is serialized as java.math.BigDecimal
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalHessian() (Any, error) {
+	return BigDecimal{Value: fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100)}, nil
+}
+
+func (m *Money) UnmarshalHessian(v Any) error {
+	var s string
+	if err := ConvertTo(v, &s); err != nil {
+		return err
+	}
+	cents, err := strconv.ParseInt(strings.Replace(s, ".", "", 1), 10, 64)
+	if err != nil {
+		return err
+	}
+	m.Cents = cents
+	return nil
+}
+
+type Order struct {
+	Id    UUID
+	Ip    net.IP
+	Price Money
+}
+
+func (Order) GetType() string         { return "test.Order" }
+func (o Order) GetId() UUID           { return o.Id }
+func (o *Order) SetId(id UUID)        { o.Id = id }
+func (o Order) GetIp() net.IP         { return o.Ip }
+func (o *Order) SetIp(ip net.IP)      { o.Ip = ip }
+func (o Order) GetPrice() Money       { return o.Price }
+func (o *Order) SetPrice(price Money) { o.Price = price }
+
+func init() {
+	RegisterSerializer(UUID{}, "java.util.UUID", uuidSerializer{})
+	RegisterSerializer(net.IP{}, "", ipSerializer{})
+	RegisterPOJO(Order{})
+}
+
+func TestSerializer(t *testing.T) {
+	var (
+		id    = UUID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+		ip    = net.ParseIP("10.0.0.1")
+		price = Money{Cents: 1234}
+		order = &Order{Id: id, Ip: ip, Price: price}
+	)
+
+	desc, err := GetParamTypes(id, ip, price, []UUID{id}, order)
+	if want := "Ljava/util/UUID;Ljava/lang/String;Ljava/math/BigDecimal;[Ljava/util/UUID;Ltest/Order;"; err != nil || desc != want {
+		t.Errorf("GetParamTypes() = %s, error:%v, want %s", desc, err, want)
+	}
+
+	var cases = []struct {
+		v    Any
+		want Any
+	}{
+		{id, id},
+		{[]UUID{id}, []UUID{id}},
+		{ip, "10.0.0.1"},
+		{[]net.IP{ip}, []Any{"10.0.0.1"}},
+		{price, &BigDecimal{Value: "12.34"}},
+		{&price, &BigDecimal{Value: "12.34"}},
+		{(*Money)(nil), nil},
+		{order, order},
+	}
+	for _, c := range cases {
+		v, err := NewDecoder(Encode(c.v, nil)).Decode()
+		if err != nil || !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decode(Encode(%#v)) = %#v, error:%v, want %#v", c.v, v, err, c.want)
+		}
+
+		e := NewEncoder2()
+		if err = e.Encode(c.v); err != nil {
+			t.Fatalf("Encoder2.Encode(%#v) = error:%v", c.v, err)
+		}
+		v, err = NewDecoder2(e.Buffer()).Decode()
+		if err != nil || !reflect.DeepEqual(v, c.want) {
+			t.Errorf("Decoder2.Decode(Encode(%#v)) = %#v, error:%v, want %#v", c.v, v, err, c.want)
+		}
+	}
+
+	// the decoded values are converted by the serializers and UnmarshalHessian
+	var (
+		ip2    net.IP
+		price2 Money
+		pp     *Money
+	)
+	if err = ConvertTo("10.0.0.1", &ip2); err != nil || !ip2.Equal(ip) {
+		t.Errorf("ConvertTo(ip) = %v, error:%v", ip2, err)
+	}
+	if err = ConvertTo(BigDecimal{Value: "12.34"}, &price2); err != nil || price2 != price {
+		t.Errorf("ConvertTo(price) = %v, error:%v", price2, err)
+	}
+	if err = ConvertTo(&BigDecimal{Value: "0.05"}, &pp); err != nil || pp == nil || pp.Cents != 5 {
+		t.Errorf("ConvertTo(*price) = %v, error:%v", pp, err)
+	}
+	if err = ConvertTo(int32(1), &ip2); err == nil {
+		t.Errorf("ConvertTo(1) = %v", ip2)
+	}
+	if err = RegisterSerializer(nil, "", ipSerializer{}); err == nil {
+		t.Errorf("RegisterSerializer(nil) = nil")
+	}
+}
@@ -0,0 +1,305 @@
+/******************************************************
+# DESC    : hessian http server which exports go objects
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 10:12
+# FILE    : server.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HESSIAN_CONTENT_TYPE = "x-application/hessian"
+)
+
+var (
+	ErrServiceRegistered = fmt.Errorf("service has been registered")
+	ErrNoExportedMethod  = fmt.Errorf("service has no exported method")
+	ErrNoSuchService     = fmt.Errorf("no such service")
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte{})
+	pojoType  = reflect.TypeOf((*POJO)(nil)).Elem()
+)
+
+// a method of the exported go object
+type serviceMethod struct {
+	method    reflect.Method
+	argTypes  []reflect.Type
+	hasResult bool // the first return value is not an error
+	hasError  bool // the last return value is an error
+}
+
+// an exported go object
+type service struct {
+	name    string
+	rcvr    reflect.Value
+	methods map[string]*serviceMethod
+}
+
+// Server exports the methods of go objects as hessian services.
+// Like com.caucho.hessian.server.HessianServlet, every service is bound to a http path.
+type Server struct {
+	sync.RWMutex
+	services map[string]*service
+}
+
+func NewServer() *Server {
+	return &Server{services: make(map[string]*service)}
+}
+
+// Register exports all the exported methods of @rcvr on http path @path.
+// Every method can be called by its go name(Add), its java style name(add),
+// and the overloaded names mangled by caucho(add__2, add_int_int).
+// A method may return nothing, a value, an error, or a value and an error.
+func (this *Server) Register(path string, rcvr Any) error {
+	var (
+		svc *service
+		err error
+	)
+
+	if svc, err = newService(path, rcvr); err != nil {
+		return err
+	}
+
+	this.Lock()
+	defer this.Unlock()
+	if _, ok := this.services[path]; ok {
+		return ErrServiceRegistered
+	}
+	this.services[path] = svc
+
+	return nil
+}
+
+// Invoke calls method @method of service @path with arguments @args.
+func (this *Server) Invoke(path string, method string, args []Any) (Any, error) {
+	this.RLock()
+	svc, ok := this.services[path]
+	this.RUnlock()
+	if !ok {
+		return nil, ErrNoSuchService
+	}
+
+	return svc.call(method, args)
+}
+
+// ServeHTTP decodes the hessian call in the http body, invokes the service
+// bound to the request path and writes the reply or the fault.
+func (this *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		b    []byte
+		call *Call
+		rsp  Any
+	)
+
+	if r.Method != "POST" {
+		http.Error(w, "hessian requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	this.RLock()
+	svc, ok := this.services[r.URL.Path]
+	this.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", HESSIAN_CONTENT_TYPE)
+	if b, err = ioutil.ReadAll(r.Body); err != nil {
+		w.Write(EncodeFault(&Fault{Code: FAULT_PROTOCOL, Message: err.Error()}, nil))
+		return
+	}
+	if call, err = NewDecoder(b).DecodeCall(); err != nil {
+		w.Write(EncodeFault(&Fault{Code: FAULT_PROTOCOL, Message: err.Error()}, nil))
+		return
+	}
+
+	if rsp, err = svc.call(call.Method, call.Args); err != nil {
+		w.Write(EncodeFault(err, nil))
+		return
+	}
+	w.Write(EncodeReply(rsp, nil))
+}
+
+func newService(name string, rcvr Any) (*service, error) {
+	var (
+		typ reflect.Type
+		svc *service
+	)
+
+	svc = &service{
+		name:    name,
+		rcvr:    reflect.ValueOf(rcvr),
+		methods: make(map[string]*serviceMethod),
+	}
+	typ = reflect.TypeOf(rcvr)
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if method.PkgPath != "" { // unexported
+			continue
+		}
+
+		mtd := &serviceMethod{method: method}
+		for j := 1; j < method.Type.NumIn(); j++ {
+			mtd.argTypes = append(mtd.argTypes, method.Type.In(j))
+		}
+		switch method.Type.NumOut() {
+		case 0:
+		case 1:
+			if method.Type.Out(0) == errorType {
+				mtd.hasError = true
+			} else {
+				mtd.hasResult = true
+			}
+		case 2:
+			if method.Type.Out(1) != errorType {
+				continue
+			}
+			mtd.hasResult = true
+			mtd.hasError = true
+		default:
+			continue
+		}
+
+		svc.methods[method.Name] = mtd
+		// java style method name: Add -> add
+		svc.methods[lowerFirst(method.Name)] = mtd
+		// overloaded method names: add__2, add_int_int
+		svc.methods[mangleName(lowerFirst(method.Name), len(mtd.argTypes))] = mtd
+		svc.methods[mangleName(method.Name, len(mtd.argTypes))] = mtd
+		svc.methods[mangleNameByTypes(lowerFirst(method.Name), mtd.argTypes)] = mtd
+		svc.methods[mangleNameByTypes(method.Name, mtd.argTypes)] = mtd
+	}
+
+	if len(svc.methods) == 0 {
+		return nil, ErrNoExportedMethod
+	}
+
+	return svc, nil
+}
+
+func (this *service) call(method string, args []Any) (rsp Any, err error) {
+	var (
+		ok     bool
+		mtd    *serviceMethod
+		in     []reflect.Value
+		out    []reflect.Value
+		argVal reflect.Value
+	)
+
+	if mtd, ok = this.methods[method]; !ok {
+		return nil, &Fault{Code: FAULT_NO_SUCH_METHOD, Message: "The service has no method named: " + method}
+	}
+	if len(args) != len(mtd.argTypes) {
+		return nil, &Fault{
+			Code:    FAULT_NO_SUCH_METHOD,
+			Message: fmt.Sprintf("method %s expects %d arguments, but got %d", method, len(mtd.argTypes), len(args)),
+		}
+	}
+
+	in = make([]reflect.Value, 0, len(args)+1)
+	in = append(in, this.rcvr)
+	for i, arg := range args {
+		if argVal, err = convertValue(arg, mtd.argTypes[i]); err != nil {
+			return nil, &Fault{Code: FAULT_SERVICE, Message: fmt.Sprintf("method %s argument %d: %s", method, i, err)}
+		}
+		in = append(in, argVal)
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			rsp = nil
+			err = &Fault{Code: FAULT_SERVICE, Message: fmt.Sprintf("%v", e)}
+		}
+	}()
+	out = mtd.method.Func.Call(in)
+	if mtd.hasError {
+		if e := out[len(out)-1].Interface(); e != nil {
+			return nil, e.(error)
+		}
+	}
+	if mtd.hasResult {
+		rsp = out[0].Interface()
+	}
+
+	return rsp, nil
+}
+
+// mangleName returns the overloaded method name used by caucho when
+// overload is enabled, such as add__2 and reset__0.
+func mangleName(method string, argc int) string {
+	return fmt.Sprintf("%s__%d", method, argc)
+}
+
+// mangleNameByTypes returns the overloaded method name composed of the
+// java types of the arguments, such as add_int_int.
+func mangleNameByTypes(method string, types []reflect.Type) string {
+	var name = method
+	for _, typ := range types {
+		name += "_" + mangleType(typ)
+	}
+
+	return name
+}
+
+// refers to com.caucho.services.server.AbstractSkeleton:mangleClass, which
+// mangles the java classes by their simple names. []Any is a java.util.List
+// (List), and the other slices are java arrays, such as []int64(long[]) is
+// mangled as [long.
+func mangleType(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "int"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "long"
+	case reflect.Float32, reflect.Float64:
+		return "double"
+	case reflect.String:
+		return "string"
+	case reflect.Map:
+		return "Map"
+	case reflect.Slice, reflect.Array:
+		if typ == bytesType {
+			return "binary"
+		}
+		if typ.Elem().Kind() == reflect.Interface {
+			return "List"
+		}
+		return "[" + mangleType(typ.Elem())
+	case reflect.Interface:
+		return "Object"
+	case reflect.Struct, reflect.Ptr:
+		if typ == timeType {
+			return "date"
+		}
+		if typ == orderedMapType || typ == typedMapType || typ == reflect.PtrTo(typedMapType) {
+			return "Map"
+		}
+		if typ.Implements(pojoType) {
+			name := getPOJOType(typ)
+			return name[strings.LastIndexAny(name, "./")+1:]
+		}
+		if typ.Kind() == reflect.Ptr {
+			return mangleType(typ.Elem())
+		}
+	}
+
+	return typ.Name()
+}
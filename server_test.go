@@ -0,0 +1,182 @@
+/******************************************************
+# DESC    : server.go unit test
+# AUTHOR  : Alex Stocks
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2026-10-19 10:12
+# FILE    : server_test.go
+******************************************************/
+
+package hessian
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// go test -v -run TestServer
+
+//go:generate go run ./cmd/hessiangen -type MathService -file server_test.go -output mathservice_hessian_test.go
+type MathService interface {
+	Add(x, y int32) (int32, error)
+	Div(x, y int32) (int32, error)
+	Sum(l []Any) (int64, error)
+	Echo(foo *Foo) (*Foo, error)
+	//hessian:method reset
+	Reset() error
+}
+
+type Math struct{}
+
+func (m *Math) Add(x, y int32) int32 {
+	return x + y
+}
+
+func (m *Math) Sub(x, y int32) int32 {
+	return x - y
+}
+
+func (m *Math) Mul(x, y int32) int32 {
+	return x * y
+}
+
+func (m *Math) Div(x, y int32) (int32, error) {
+	if y == 0 {
+		return 0, fmt.Errorf("divide by zero")
+	}
+	return x / y, nil
+}
+
+func (m *Math) Sum(l []int64) int64 {
+	var sum int64
+	for _, v := range l {
+		sum += v
+	}
+	return sum
+}
+
+func (m *Math) Echo(foo *Foo) *Foo {
+	return foo
+}
+
+func (m *Math) Reset() {}
+
+func TestServerInvoke(t *testing.T) {
+	var (
+		err error
+		res Any
+		s   = NewServer()
+	)
+
+	if err = s.Register("/math", &Math{}); err != nil {
+		t.Fatalf("Register() = error:%v", err)
+	}
+	if err = s.Register("/math", &Math{}); err != ErrServiceRegistered {
+		t.Fatalf("Register() again = error:%v", err)
+	}
+
+	for _, method := range []string{"Add", "add", "add__2", "Add__2", "add_int_int"} {
+		// int is encoded as long
+		res, err = s.Invoke("/math", method, []Any{int64(100), int32(200)})
+		if err != nil || res != int32(300) {
+			t.Errorf("%s(100, 200) = res:%v, err:%v", method, res, err)
+		}
+	}
+
+	if res, err = s.Invoke("/math", "sum", []Any{[]Any{int32(1), int64(2), int32(3)}}); err != nil || res != int64(6) {
+		t.Errorf("sum([1, 2, 3]) = res:%v, err:%v", res, err)
+	}
+	if res, err = s.Invoke("/math", "reset", nil); err != nil || res != nil {
+		t.Errorf("reset() = res:%v, err:%v", res, err)
+	}
+	if _, err = s.Invoke("/math", "div", []Any{int32(1), int32(0)}); err == nil || err.Error() != "divide by zero" {
+		t.Errorf("div(1, 0) = err:%v", err)
+	}
+	if _, err = s.Invoke("/math", "pow", []Any{int32(1), int32(0)}); err == nil {
+		t.Errorf("pow(1, 0) should fail")
+	}
+	if _, err = s.Invoke("/math", "add", []Any{int32(1)}); err == nil {
+		t.Errorf("add(1) should fail")
+	}
+	if _, err = s.Invoke("/math", "add", []Any{"1", int32(1)}); err == nil {
+		t.Errorf("add(\"1\", 1) should fail")
+	}
+	if _, err = s.Invoke("/echo", "add", nil); err != ErrNoSuchService {
+		t.Errorf("Invoke(/echo) = err:%v", err)
+	}
+}
+
+func TestServerHTTP(t *testing.T) {
+	var (
+		err error
+		res Any
+		s   = NewServer()
+	)
+
+	RegisterPOJO(Foo{})
+	s.Register("/math", &Math{})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	if res, err = Request(ts.URL+"/math", "Add", 100, 200); err != nil || res != int32(300) {
+		t.Errorf("Add(100, 200) = res:%v, err:%v", res, err)
+	}
+	if res, err = Request(ts.URL+"/math", "Div", 200, 50); err != nil || res != int32(4) {
+		t.Errorf("Div(200, 50) = res:%v, err:%v", res, err)
+	}
+	if res, err = Request(ts.URL+"/math", "Sum", []Any{1, 2, 3}); err != nil || res != int64(6) {
+		t.Errorf("Sum([1, 2, 3]) = res:%v, err:%v", res, err)
+	}
+	if res, err = Request(ts.URL+"/math", "Echo", &Foo{bar: 100, baz: "baz"}); err != nil {
+		t.Errorf("Echo(Foo) = res:%v, err:%v", res, err)
+	} else if foo, ok := res.(*Foo); !ok || foo.bar != 100 || foo.baz != "baz" {
+		t.Errorf("Echo(Foo) = res:%#v", res)
+	}
+
+	_, err = Request(ts.URL+"/math", "Div", 200, 0)
+	if err == nil || !strings.Contains(err.Error(), "divide by zero") {
+		t.Errorf("Div(200, 0) = err:%v", err)
+	}
+	_, err = Request(ts.URL+"/math", "Pow", 200, 0)
+	if err == nil || !strings.Contains(err.Error(), FAULT_NO_SUCH_METHOD) {
+		t.Errorf("Pow(200, 0) = err:%v", err)
+	}
+	if _, err = Request(ts.URL+"/echo", "Add", 100, 200); err == nil {
+		t.Errorf("Request(/echo) should fail")
+	}
+}
+
+func TestServerProxy(t *testing.T) {
+	var (
+		err error
+		res int32
+		sum int64
+		foo *Foo
+		s   = NewServer()
+	)
+
+	RegisterPOJO(Foo{})
+	s.Register("/math", &Math{})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	var math MathService = NewMathServiceProxy(NewClient(ts.URL + "/math"))
+	if res, err = math.Add(100, 200); err != nil || res != 300 {
+		t.Errorf("Add(100, 200) = res:%v, err:%v", res, err)
+	}
+	if sum, err = math.Sum([]Any{1, 2, 3}); err != nil || sum != 6 {
+		t.Errorf("Sum([1, 2, 3]) = res:%v, err:%v", sum, err)
+	}
+	if foo, err = math.Echo(&Foo{bar: 100, baz: "baz"}); err != nil || foo.bar != 100 || foo.baz != "baz" {
+		t.Errorf("Echo(Foo) = res:%#v, err:%v", foo, err)
+	}
+	if err = math.Reset(); err != nil {
+		t.Errorf("Reset() = err:%v", err)
+	}
+	if _, err = math.Div(100, 0); err == nil {
+		t.Errorf("Div(100, 0) should fail")
+	} else if fault, ok := err.(*Fault); !ok || fault.Code != FAULT_SERVICE {
+		t.Errorf("Div(100, 0) = err:%#v", err)
+	}
+}